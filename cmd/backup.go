@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mholtzscher/aerospace-utils/cmd/workspace"
+	"github.com/mholtzscher/aerospace-utils/internal/cli"
+	"github.com/mholtzscher/aerospace-utils/internal/config"
+	"github.com/mholtzscher/aerospace-utils/internal/output"
+	ufcli "github.com/urfave/cli/v3"
+)
+
+// newBackupCommand creates the backup command, which lists and restores the
+// copies of aerospace.toml and the state file taken before each write.
+func newBackupCommand() *ufcli.Command {
+	return &ufcli.Command{
+		Name:  "backup",
+		Usage: "List and restore backups of aerospace.toml and the state file",
+		Description: `Before aerospace.toml or the state file is written, a timestamped copy of
+the old file is saved. The newest --backups copies of each file are kept
+(10 by default, 0 disables backups) in --backup-dir, or in
+$XDG_STATE_HOME/aerospace-utils/backups (~/.local/state by default), one
+subdirectory per file, when no directory is set.
+Both can also be set with ` + cli.EnvBackups + ` and ` + cli.EnvBackupDir + `.
+
+Examples:
+  aerospace-utils backup list
+  aerospace-utils backup restore 20261016-162311.042-config
+  aerospace-utils backup restore 20261016-162311.042-config --no-reload`,
+		Commands: []*ufcli.Command{
+			{
+				Name:  "list",
+				Usage: "List backups, newest first",
+				Action: func(ctx context.Context, cmd *ufcli.Command) error {
+					return runBackupList(cmd)
+				},
+			},
+			{
+				Name:      "restore",
+				Usage:     "Put a backup back in place",
+				ArgsUsage: "<id>",
+				Description: `Copy a backup over the file it was taken from: aerospace.toml for config
+backups, the state file for state backups. The file being replaced is
+backed up first, so a restore can itself be undone. Restoring
+aerospace.toml reloads Aerospace unless --no-reload is given.`,
+				Action: func(ctx context.Context, cmd *ufcli.Command) error {
+					return workspace.WithLock(cli.GetOptions(cmd), func() error { return runBackupRestore(cmd) })
+				},
+			},
+		},
+	}
+}
+
+// backupReport is the structured form of a backup.
+type backupReport struct {
+	ID   string    `json:"id"`
+	Kind string    `json:"kind"`
+	Time time.Time `json:"time"`
+	Path string    `json:"path"`
+	Size int64     `json:"size"`
+}
+
+func newBackupReport(b config.Backup) backupReport {
+	return backupReport{ID: b.ID, Kind: string(b.Kind), Time: b.Time, Path: b.Path, Size: b.Size}
+}
+
+// backupDirs returns the directories holding backups of the config and
+// state files.
+func backupDirs(opts *cli.GlobalOptions) (config.Backups, []string) {
+	configSvc, stateSvc := workspace.NewServices(opts)
+	backups := workspace.BackupsFor(opts)
+	return backups, []string{backups.DirFor(configSvc.ConfigPath()), backups.DirFor(stateSvc.StatePath())}
+}
+
+func runBackupList(cmd *ufcli.Command) error {
+	opts := cli.GetOptions(cmd)
+	out := output.New(opts.NoColor)
+	out.SetWriter(opts.Stdout)
+
+	backups, dirs := backupDirs(opts)
+	list, err := backups.List(dirs...)
+	if err != nil {
+		return err
+	}
+
+	if opts.Output.Structured() {
+		reports := make([]backupReport, 0, len(list))
+		for _, b := range list {
+			reports = append(reports, newBackupReport(b))
+		}
+		return output.EncodeTo(opts.Stdout, opts.Output, struct {
+			Backups []backupReport `json:"backups"`
+		}{reports})
+	}
+
+	out.PrintHeader("Backups")
+	if len(list) == 0 {
+		out.Unset("  (no backups)\n")
+		return nil
+	}
+	for _, b := range list {
+		out.Label("  %s", b.ID)
+		out.Value("  %s  %d bytes\n", b.Time.Format(time.DateTime), b.Size)
+	}
+	return nil
+}
+
+func runBackupRestore(cmd *ufcli.Command) error {
+	opts := cli.GetOptions(cmd)
+	out := workspace.NewPrinter(opts)
+
+	if cmd.Args().Len() == 0 || cmd.Args().First() == "" {
+		return errors.New("backup id required; see 'backup list'")
+	}
+
+	backups, dirs := backupDirs(opts)
+	backup, err := backups.Find(cmd.Args().First(), dirs...)
+	if err != nil {
+		return err
+	}
+
+	configSvc, stateSvc := workspace.NewServices(opts)
+	path := stateSvc.StatePath()
+	if backup.Kind == config.BackupConfig {
+		path = configSvc.ConfigPath()
+	}
+
+	reload := workspace.ReloadResult{Status: workspace.ReloadSkipped, Message: "dry run"}
+	if !opts.DryRun {
+		if err := backups.Restore(backup, path); err != nil {
+			return err
+		}
+		reload = workspace.ReloadResult{Status: workspace.ReloadSkipped, Message: "state file"}
+		if backup.Kind == config.BackupConfig {
+			reload = workspace.ReloadAerospace(opts)
+		}
+	}
+
+	if opts.Output.Structured() {
+		return output.EncodeTo(opts.Stdout, opts.Output, struct {
+			DryRun bool                   `json:"dry_run"`
+			Backup backupReport           `json:"backup"`
+			Target string                 `json:"target"`
+			Reload workspace.ReloadResult `json:"reload"`
+		}{opts.DryRun, newBackupReport(backup), path, reload})
+	}
+
+	if opts.DryRun {
+		out.DryRun()
+		out.Printf("Would restore %s to %s\n", backup.ID, path)
+		return nil
+	}
+	suffix := ""
+	if backup.Kind == config.BackupConfig {
+		suffix = reload.Suffix()
+	}
+	out.Success("Restored %s to %s%s\n", backup.ID, path, suffix)
+	return nil
+}
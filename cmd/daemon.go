@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mholtzscher/aerospace-utils/cmd/workspace"
+	"github.com/mholtzscher/aerospace-utils/internal/cli"
+	"github.com/mholtzscher/aerospace-utils/internal/display"
+	"github.com/mholtzscher/aerospace-utils/internal/output"
+	ufcli "github.com/urfave/cli/v3"
+)
+
+const (
+	flagInterval = "interval"
+	flagDebounce = "debounce"
+	flagOnce     = "once"
+)
+
+// newDaemonCommand creates the daemon command, which keeps layouts in step
+// with the connected displays.
+func newDaemonCommand() *ufcli.Command {
+	return &ufcli.Command{
+		Name:  "daemon",
+		Usage: "Reapply saved layouts when displays change",
+		Description: `Watch the connected displays and, whenever they change (docking,
+undocking, changing resolution), recalculate the gaps of every connected
+monitor from its saved layout, write the config once and reload aerospace.
+
+Layouts are reapplied when the daemon starts. Display changes are only acted
+on once they have stayed the same for --debounce, so a monitor that flaps
+while docking triggers a single update. Everything the daemon does is logged
+to stderr.
+
+With --once, layouts are reapplied for the current displays and the command
+exits, which suits wake or display hooks.
+
+Examples:
+  aerospace-utils daemon
+  aerospace-utils daemon --interval 5s --debounce 10s
+  aerospace-utils daemon --once`,
+		Flags: []ufcli.Flag{
+			&ufcli.DurationFlag{
+				Name:  flagInterval,
+				Value: 2 * time.Second,
+				Usage: "How often to check the displays",
+			},
+			&ufcli.DurationFlag{
+				Name:  flagDebounce,
+				Value: 3 * time.Second,
+				Usage: "How long a display change must last before layouts are reapplied",
+			},
+			&ufcli.BoolFlag{
+				Name:  flagOnce,
+				Usage: "Reapply layouts for the current displays and exit",
+			},
+		},
+		Action: func(ctx context.Context, cmd *ufcli.Command) error {
+			return runDaemon(ctx, cmd)
+		},
+	}
+}
+
+func runDaemon(ctx context.Context, cmd *ufcli.Command) error {
+	opts := cli.GetOptions(cmd)
+	if opts.Output.Structured() {
+		return fmt.Errorf("daemon does not support --%s %s", cli.FlagOutput, opts.Output)
+	}
+	if root := cmd.Root(); root.IsSet(cli.FlagMonitor) || root.IsSet(cli.FlagAll) {
+		return fmt.Errorf("daemon reapplies every monitor; --%s and --%s are not supported", cli.FlagMonitor, cli.FlagAll)
+	}
+
+	interval := cmd.Duration(flagInterval)
+	if interval <= 0 {
+		return fmt.Errorf("--%s must be positive", flagInterval)
+	}
+
+	// Log lines are timestamped, so escape codes would only get in the way.
+	out := output.New(true)
+	out.SetWriter(output.NewLineLogger(opts.Stderr))
+
+	displays, err := display.Enumerate()
+	if err != nil {
+		return fmt.Errorf("enumerate displays: %w", err)
+	}
+	out.Printf("Displays: %s\n", describeDisplays(displays))
+	workspace.Reapply(opts, out, displays)
+
+	if cmd.Bool(flagOnce) {
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	out.Printf("Watching displays every %s (debounce %s)\n", interval, cmd.Duration(flagDebounce))
+	watcher := display.Watcher{
+		Interval: interval,
+		Debounce: cmd.Duration(flagDebounce),
+		OnError: func(err error) {
+			out.Warning("Display check failed: %v\n", err)
+		},
+	}
+	err = watcher.Watch(ctx, func(displays []display.Info) {
+		out.Printf("Displays changed: %s\n", describeDisplays(displays))
+		workspace.Reapply(opts, out, displays)
+	})
+	if errors.Is(err, context.Canceled) {
+		out.Printf("Stopped\n")
+		return nil
+	}
+	return err
+}
+
+// describeDisplays summarizes displays for the log, e.g.
+// "DELL U2722D 2560x1440 (main), Built-in Retina Display 1512x982".
+func describeDisplays(displays []display.Info) string {
+	parts := make([]string, 0, len(displays))
+	for _, d := range displays {
+		part := fmt.Sprintf("%s %dx%d", d.Name, d.Width, d.Height)
+		if d.Main {
+			part += " (main)"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mholtzscher/aerospace-utils/cmd/workspace"
+	"github.com/mholtzscher/aerospace-utils/internal/cli"
+	ufcli "github.com/urfave/cli/v3"
+)
+
+// newRenderCommand creates the render command, which prints the config with
+// the managed gaps merged in.
+func newRenderCommand() *ufcli.Command {
+	return &ufcli.Command{
+		Name:  "render",
+		Usage: "Print aerospace.toml with the gaps from the state file merged in",
+		Description: `Print the base config with the gaps last applied to each monitor merged in,
+exactly as it is written in overlay mode. Nothing is written.
+
+Overlay mode is for configs that cannot be edited, such as one generated by
+home-manager into the Nix store. Set --base-config (or ` + cli.EnvBaseConfig + `)
+to the read-only config and --config-path to a writable file that Aerospace
+is started with; every change then renders the base config with the managed
+gaps into that file. Without --base-config, the file at --config-path is
+used as the base.
+
+Examples:
+  aerospace-utils render --base-config ~/.config/aerospace/aerospace.toml
+  aerospace-utils render > ~/.cache/aerospace/aerospace.toml`,
+		Action: func(ctx context.Context, cmd *ufcli.Command) error {
+			return runRender(cmd)
+		},
+	}
+}
+
+func runRender(cmd *ufcli.Command) error {
+	opts := cli.GetOptions(cmd)
+	if opts.Output.Structured() {
+		return fmt.Errorf("render prints TOML; --%s %s is not supported", cli.FlagOutput, opts.Output)
+	}
+
+	configSvc, stateSvc := workspace.NewServices(opts)
+	if configSvc.BasePath() == "" {
+		configSvc.SetOverlay(configSvc.ConfigPath(), stateSvc)
+	}
+
+	content, err := configSvc.Content()
+	if err != nil {
+		return fmt.Errorf("render config: %w", err)
+	}
+	_, err = opts.Stdout.Write(content)
+	return err
+}
@@ -3,18 +3,30 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/mholtzscher/aerospace-utils/cmd/workspace"
 	"github.com/mholtzscher/aerospace-utils/internal/cli"
+	"github.com/mholtzscher/aerospace-utils/internal/config"
+	"github.com/mholtzscher/aerospace-utils/internal/gaps"
+	"github.com/mholtzscher/aerospace-utils/internal/output"
 	ufcli "github.com/urfave/cli/v3"
 )
 
 // Version is set at build time.
 var Version = "0.3.3" // x-release-please-version
 
-// Run is the entry point for the CLI.
+// Run is the entry point for the CLI. Commands that a running server can
+// handle are forwarded to it.
 func Run(ctx context.Context, args []string) error {
-	app := &ufcli.Command{
+	app := newApp()
+	forwardToServer(app, args)
+	return app.Run(ctx, args)
+}
+
+// newApp builds the command tree.
+func newApp() *ufcli.Command {
+	return &ufcli.Command{
 		Name:    "aerospace-utils",
 		Usage:   "CLI for managing Aerospace workspace sizing",
 		Version: Version,
@@ -30,7 +42,7 @@ func Run(ctx context.Context, args []string) error {
 			&ufcli.StringFlag{
 				Name:  cli.FlagMonitor,
 				Value: "main",
-				Usage: "Target monitor name",
+				Usage: `Target monitor name, or "focused" for the monitor with focus`,
 			},
 			&ufcli.IntFlag{
 				Name:   cli.FlagMonitorWidth,
@@ -38,10 +50,24 @@ func Run(ctx context.Context, args []string) error {
 				Hidden: true,
 				Usage:  "Override detected monitor width in pixels",
 			},
+			&ufcli.IntFlag{
+				Name:   cli.FlagMonitorHeight,
+				Value:  0,
+				Hidden: true,
+				Usage:  "Override detected monitor height in pixels",
+			},
+			&ufcli.BoolFlag{
+				Name:  cli.FlagAll,
+				Usage: "Target every monitor in aerospace.toml (with --create, also detected displays)",
+			},
 			&ufcli.BoolFlag{
 				Name:  cli.FlagNoReload,
 				Usage: "Skip aerospace reload-config after changes",
 			},
+			&ufcli.BoolFlag{
+				Name:  cli.FlagCreate,
+				Usage: "Create missing per-monitor gap entries in aerospace.toml",
+			},
 			&ufcli.BoolFlag{
 				Name:  cli.FlagDryRun,
 				Usage: "Print actions without writing changes",
@@ -54,11 +80,58 @@ func Run(ctx context.Context, args []string) error {
 				Name:  cli.FlagNoColor,
 				Usage: "Disable colored output",
 			},
+			&ufcli.StringFlag{
+				Name:  cli.FlagOutput,
+				Value: string(output.FormatText),
+				Usage: "Output format: text, json or yaml",
+				Validator: func(s string) error {
+					_, err := output.ParseFormat(s)
+					return err
+				},
+			},
+			&ufcli.StringFlag{
+				Name:  cli.FlagRounding,
+				Value: string(gaps.RoundNearest),
+				Usage: "Pixel rounding for gaps: nearest, floor or even",
+				Validator: func(s string) error {
+					_, err := gaps.ParseRounding(s)
+					return err
+				},
+			},
+			&ufcli.IntFlag{
+				Name:    cli.FlagBackups,
+				Value:   config.DefaultBackupKeep,
+				Usage:   "Backups to keep of aerospace.toml and the state file, taken before each write (0 disables)",
+				Sources: ufcli.EnvVars(cli.EnvBackups),
+				Validator: func(n int) error {
+					if n < 0 {
+						return fmt.Errorf("--%s must not be negative", cli.FlagBackups)
+					}
+					return nil
+				},
+			},
+			&ufcli.StringFlag{
+				Name:    cli.FlagBackupDir,
+				Usage:   "Directory for backups (default: $XDG_STATE_HOME/aerospace-utils/backups)",
+				Sources: ufcli.EnvVars(cli.EnvBackupDir),
+			},
+			&ufcli.StringFlag{
+				Name:    cli.FlagBaseConfig,
+				Usage:   "Read-only aerospace.toml to render from; changes are written to --config-path instead",
+				Sources: ufcli.EnvVars(cli.EnvBaseConfig),
+			},
+			&ufcli.BoolFlag{
+				Name:    cli.FlagRollback,
+				Usage:   "Restore aerospace.toml and the state file when aerospace reload-config fails",
+				Sources: ufcli.EnvVars(cli.EnvRollback),
+			},
 		},
 		Commands: []*ufcli.Command{
 			workspace.NewCommand(),
+			newDaemonCommand(),
+			newBackupCommand(),
+			newRenderCommand(),
+			newServeCommand(),
 		},
 	}
-
-	return app.Run(ctx, args)
 }
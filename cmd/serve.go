@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mholtzscher/aerospace-utils/internal/cli"
+	"github.com/mholtzscher/aerospace-utils/internal/control"
+	"github.com/mholtzscher/aerospace-utils/internal/output"
+	ufcli "github.com/urfave/cli/v3"
+)
+
+const (
+	flagSocket = "socket"
+	flagHTTP   = "http"
+)
+
+// servedCommands are the commands a running server takes over from clients.
+var servedCommands = [][]string{
+	{"workspace", "use"},
+	{"workspace", "adjust"},
+	{"workspace", "shift"},
+	{"workspace", "current"},
+}
+
+func newServeCommand() *ufcli.Command {
+	return &ufcli.Command{
+		Name:  "serve",
+		Usage: "Run a server that handles workspace commands without starting a new process",
+		Description: `Keep aerospace-utils running and answer workspace use, adjust, shift
+and current requests over a Unix socket. While it runs, those commands
+forward to it instead of doing the work themselves, which makes keybindings
+respond faster. Requests are handled one at a time.
+
+The socket defaults to /tmp/aerospace-utils-$USER.sock; set
+` + control.EnvSocket + ` to change it for both the server and clients.
+Forwarded commands run with the server's environment and with the global
+flags it was started with, such as --config-path, unless the client sets
+them. Clients only forward to a socket owned by the same user.
+
+With --http, the same commands are also served as a JSON API on a localhost
+address, for tools like a Stream Deck plugin:
+
+  GET  /current                  current layouts
+  POST /use     {"size": 60}
+  POST /adjust  {"by": 5}
+  POST /shift   {"by": -5}
+
+POST bodies may also set monitor, all, dry_run and no_reload (and
+set_default for use), and must be sent as application/json.
+
+Examples:
+  aerospace-utils serve
+  aerospace-utils serve --http 127.0.0.1:7878`,
+		Flags: []ufcli.Flag{
+			&ufcli.StringFlag{
+				Name:  flagSocket,
+				Usage: "Unix socket to listen on (default: $" + control.EnvSocket + " or /tmp/aerospace-utils-$USER.sock)",
+			},
+			&ufcli.StringFlag{
+				Name:  flagHTTP,
+				Usage: "Also serve a JSON API on this localhost address, e.g. 127.0.0.1:7878",
+				Validator: func(s string) error {
+					return control.CheckLoopback(s)
+				},
+			},
+		},
+		Action: func(ctx context.Context, cmd *ufcli.Command) error {
+			return runServe(ctx, cmd)
+		},
+	}
+}
+
+func runServe(ctx context.Context, cmd *ufcli.Command) error {
+	opts := cli.GetOptions(cmd)
+	out := output.New(true)
+	out.SetWriter(output.NewLineLogger(opts.Stderr))
+
+	path := cmd.String(flagSocket)
+	if path == "" {
+		path = control.DefaultSocketPath()
+	}
+	ln, err := control.Listen(path)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := control.NewServer(inProcessRunner(globalArgs(cmd.Root())))
+	server.Logf = out.Printf
+
+	if addr := cmd.String(flagHTTP); addr != "" {
+		httpServer := &http.Server{Addr: addr, Handler: server.Handler(addr), ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				out.Error("HTTP server failed: %v\n", err)
+				stop()
+			}
+		}()
+		defer func() { _ = httpServer.Close() }()
+		out.Printf("Serving HTTP on %s\n", addr)
+	}
+
+	out.Printf("Listening on %s\n", path)
+	if err := server.Serve(ctx, ln); err != nil {
+		return err
+	}
+	out.Printf("Stopped\n")
+	return nil
+}
+
+// globalArgs returns the global flags set on the server's command line,
+// such as --config-path, so forwarded commands use the same files.
+func globalArgs(root *ufcli.Command) []string {
+	var args []string
+	for _, f := range root.Flags {
+		name := f.Names()[0]
+		if root.IsSet(name) {
+			args = append(args, fmt.Sprintf("--%s=%v", name, root.Value(name)))
+		}
+	}
+	return args
+}
+
+// inProcessRunner returns a runner for forwarded command lines that starts
+// from the server's global flags; flags the client sets take precedence.
+func inProcessRunner(defaults []string) control.Runner {
+	return func(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+		return runInProcess(ctx, append(slices.Clone(defaults), args...), stdout, stderr)
+	}
+}
+
+// runInProcess runs a forwarded command line in the server process. Only the
+// served commands are available.
+func runInProcess(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	app := newApp()
+	app.Writer = stdout
+	app.ErrWriter = stderr
+	// Errors are returned to the client; never exit the server.
+	app.ExitErrHandler = func(context.Context, *ufcli.Command, error) {}
+
+	visitCommands(app, nil, func(path []string, c *ufcli.Command) {
+		if c.Action == nil || isServed(path) {
+			return
+		}
+		name := strings.Join(path, " ")
+		c.Action = func(context.Context, *ufcli.Command) error {
+			return fmt.Errorf("%s is not available from the server", name)
+		}
+	})
+
+	// urfave/cli finds a command's parent through the context, so start
+	// from a fresh one to keep the forwarded command from seeing the
+	// server's flags. Cancellation still carries over.
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer context.AfterFunc(ctx, cancel)()
+
+	return app.Run(runCtx, append([]string{app.Name}, args...))
+}
+
+// forwardToServer makes the served commands forward args to a running
+// server, falling back to running locally when none is listening.
+func forwardToServer(app *ufcli.Command, args []string) {
+	visitCommands(app, nil, func(path []string, c *ufcli.Command) {
+		if !isServed(path) {
+			return
+		}
+		local := c.Action
+		c.Action = func(ctx context.Context, cmd *ufcli.Command) error {
+			dir, _ := os.Getwd()
+			resp, err := control.NewClient(control.DefaultSocketPath()).Run(control.Request{Args: args[1:], Dir: dir})
+			if errors.Is(err, control.ErrUnavailable) {
+				return local(ctx, cmd)
+			}
+			if err != nil {
+				return err
+			}
+
+			root := cmd.Root()
+			_, _ = io.WriteString(root.Writer, resp.Stdout)
+			_, _ = io.WriteString(root.ErrWriter, resp.Stderr)
+			if resp.Error != "" {
+				return errors.New(resp.Error)
+			}
+			return nil
+		}
+	})
+}
+
+// visitCommands calls fn for every command below c with its path of names.
+func visitCommands(c *ufcli.Command, path []string, fn func([]string, *ufcli.Command)) {
+	for _, sub := range c.Commands {
+		subPath := append(slices.Clone(path), sub.Name)
+		fn(subPath, sub)
+		visitCommands(sub, subPath, fn)
+	}
+}
+
+func isServed(path []string) bool {
+	return slices.ContainsFunc(servedCommands, func(served []string) bool {
+		return slices.Equal(served, path)
+	})
+}
@@ -2,11 +2,9 @@ package workspace
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/mholtzscher/aerospace-utils/internal/cli"
-	"github.com/mholtzscher/aerospace-utils/internal/config"
 	"github.com/mholtzscher/aerospace-utils/internal/gaps"
 	ufcli "github.com/urfave/cli/v3"
 )
@@ -21,16 +19,18 @@ func newAdjustCommand() *ufcli.Command {
 
 Positive values increase the workspace size (smaller gaps).
 Negative values decrease the workspace size (larger gaps).
-Default adjustment is +5.
+Default adjustment is +5. Fractional amounts such as 0.5 are allowed.
 
 Examples:
   aerospace-utils workspace adjust           # +5%
   aerospace-utils workspace adjust -b 10     # +10%
   aerospace-utils workspace adjust -b -5     # -5%
   aerospace-utils workspace adjust --by=-10  # -10%
-  aerospace-utils workspace adjust -b -10 --monitor "Dell U2722D"`,
+  aerospace-utils workspace adjust -b 0.5    # +0.5%
+  aerospace-utils workspace adjust -b -10 --monitor "Dell U2722D"
+  aerospace-utils workspace adjust -b 5 --all`,
 		Flags: []ufcli.Flag{
-			&ufcli.IntFlag{
+			&ufcli.FloatFlag{
 				Name:    flagBy,
 				Aliases: []string{"b"},
 				Value:   5,
@@ -38,36 +38,53 @@ Examples:
 			},
 		},
 		Action: func(ctx context.Context, cmd *ufcli.Command) error {
-			return runAdjust(cmd)
+			return WithLock(cli.GetOptions(cmd), func() error { return runAdjust(cmd) })
 		},
 	}
 }
 
 func runAdjust(cmd *ufcli.Command) error {
 	opts := cli.GetOptions(cmd)
+	out := NewPrinter(opts)
 
-	amount := cmd.Int(flagBy)
+	amount := cmd.Float(flagBy)
 
-	// Create workspace service
-	stateSvc := config.NewWorkspaceService(opts.StatePath)
-
-	// Get current percentage for this monitor
-	monState, err := stateSvc.GetMonitorState(opts.Monitor)
+	monitors, err := targetMonitors(opts)
 	if err != nil {
-		return fmt.Errorf("load state: %w", err)
-	}
-	if monState.Current == nil {
-		return errors.New("no current percentage set; use 'workspace use' first")
+		return err
 	}
 
-	// Calculate new percentage
-	newPercent := *monState.Current + int64(amount)
+	// Create workspace service
+	_, stateSvc := NewServices(opts)
+
+	var reqs []layoutRequest
+	for _, monitor := range monitors {
+		// Get current percentage for this monitor
+		monState, err := stateSvc.GetMonitorState(monitor)
+		if err != nil {
+			return fmt.Errorf("load state: %w", err)
+		}
+		if monState.Current == nil {
+			if opts.All {
+				out.Warning("Skipping %s: no current percentage set\n", monitor)
+				continue
+			}
+			return errNoCurrent
+		}
+
+		// Calculate new percentage
+		newPercent := gaps.RoundPercentage(*monState.Current + amount)
 
-	// Validate new percentage
-	if err := gaps.ValidatePercentage(newPercent); err != nil {
-		return fmt.Errorf("adjusted percentage %d is invalid: %w", newPercent, err)
+		// Validate new percentage
+		if err := gaps.ValidatePercentage(newPercent); err != nil {
+			return fmt.Errorf("adjusted percentage %s for %s is invalid: %w", formatPercent(newPercent), monitor, err)
+		}
+
+		reqs = append(reqs, layoutRequest{monitor: monitor, percentage: &newPercent})
+	}
+	if len(reqs) == 0 {
+		return errNoCurrent
 	}
 
-	// Delegate to gaps use
-	return RunWithPercent(cmd, newPercent)
+	return applyLayouts(opts, out, reqs)
 }
@@ -0,0 +1,89 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mholtzscher/aerospace-utils/internal/cli"
+	"github.com/mholtzscher/aerospace-utils/internal/gaps"
+	ufcli "github.com/urfave/cli/v3"
+)
+
+const (
+	flagAlign  = "align"
+	flagMargin = "margin"
+)
+
+func newAlignCommand() *ufcli.Command {
+	return &ufcli.Command{
+		Name:      "align",
+		Usage:     "Pin the workspace to the left or right edge",
+		ArgsUsage: "<left|center|right>",
+		Description: `Place the workspace flush against the left or right edge of the monitor,
+keeping its current size. With --margin, leave that many pixels between the
+workspace and the edge.
+
+The alignment is remembered, so later use and adjust keep the workspace
+pinned to the same edge. Align center (or shift) to go back to a centered
+workspace.
+
+Examples:
+  aerospace-utils workspace align left
+  aerospace-utils workspace align right --margin 20
+  aerospace-utils workspace align center`,
+		Flags: []ufcli.Flag{
+			&ufcli.IntFlag{
+				Name:  flagMargin,
+				Usage: "Pixels between the workspace and the aligned edge",
+			},
+		},
+		Action: func(ctx context.Context, cmd *ufcli.Command) error {
+			return WithLock(cli.GetOptions(cmd), func() error { return runAlign(cmd) })
+		},
+	}
+}
+
+func runAlign(cmd *ufcli.Command) error {
+	opts := cli.GetOptions(cmd)
+	out := NewPrinter(opts)
+
+	if cmd.Args().Len() != 1 {
+		return errors.New("alignment required: left, center or right")
+	}
+	align, err := gaps.ParseAlignment(cmd.Args().First())
+	if err != nil {
+		return err
+	}
+	margin := int64(cmd.Int(flagMargin))
+
+	monitors, err := targetMonitors(opts)
+	if err != nil {
+		return err
+	}
+
+	reqs := make([]layoutRequest, 0, len(monitors))
+	for _, monitor := range monitors {
+		reqs = append(reqs, layoutRequest{monitor: monitor, align: &align, margin: &margin})
+	}
+	return applyLayouts(opts, out, reqs)
+}
+
+// alignmentFlags returns the --align and --margin values, or nil for flags
+// that were not set.
+func alignmentFlags(cmd *ufcli.Command) (*gaps.Alignment, *int64, error) {
+	var align *gaps.Alignment
+	if cmd.IsSet(flagAlign) {
+		a, err := gaps.ParseAlignment(cmd.String(flagAlign))
+		if err != nil {
+			return nil, nil, err
+		}
+		align = &a
+	}
+
+	var margin *int64
+	if cmd.IsSet(flagMargin) {
+		m := int64(cmd.Int(flagMargin))
+		margin = &m
+	}
+	return align, margin, nil
+}
@@ -0,0 +1,678 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mholtzscher/aerospace-utils/internal/aerospace"
+	"github.com/mholtzscher/aerospace-utils/internal/cli"
+	"github.com/mholtzscher/aerospace-utils/internal/config"
+	"github.com/mholtzscher/aerospace-utils/internal/display"
+	"github.com/mholtzscher/aerospace-utils/internal/gaps"
+	"github.com/mholtzscher/aerospace-utils/internal/output"
+)
+
+// historyOp selects how a layout change is recorded in the state history.
+type historyOp int
+
+const (
+	historyRecord historyOp = iota // push the previous layout onto the history
+	historyUndo                    // pop the history, pushing onto redo
+	historyRedo                    // pop redo, pushing onto the history
+	historyNone                    // replace the layout without recording it
+)
+
+// layoutRequest describes the layout to produce on one monitor.
+type layoutRequest struct {
+	monitor    string
+	percentage *float64        // nil resolves from current/default state
+	shift      *int64          // nil keeps the stored shift
+	height     *float64        // nil leaves the vertical gaps alone
+	size       *gaps.Size      // absolute width; overrides percentage
+	align      *gaps.Alignment // nil keeps the stored alignment
+	margin     *int64          // nil keeps the stored margin, or 0 with align
+	setDefault bool
+	history    historyOp
+
+	// displays is a snapshot of the connected displays to size the layout
+	// for, such as the one the daemon reacted to; nil detects them.
+	displays []display.Info
+
+	// resetHeight puts the vertical gaps back to the config's defaults, for
+	// undoing a layout that set a height to one that did not.
+	resetHeight bool
+}
+
+// layoutPlan is a resolved layoutRequest with its calculated gaps.
+type layoutPlan struct {
+	req        layoutRequest
+	width      int64
+	percentage float64
+	size       string // absolute width such as "2560px"; empty for percentages
+	shift      int64
+	align      gaps.Alignment
+	margin     int64
+	gaps       gaps.ShiftedGaps
+	height     float64 // vertical percentage; 0 when not requested
+	vertical   gaps.VerticalGaps
+
+	stateChanged bool // set by commitLayouts
+}
+
+// gapMessage describes the planned gaps, e.g. "(384px gaps)".
+func (p layoutPlan) gapMessage() string {
+	msg := fmt.Sprintf("(%dpx gaps)", p.gaps.LeftGapPixels)
+	if p.shift != 0 || p.aligned() {
+		msg = fmt.Sprintf("(left: %dpx (%d%%), right: %dpx (%d%%))",
+			p.gaps.LeftGapPixels, p.gaps.LeftGapPercent,
+			p.gaps.RightGapPixels, p.gaps.RightGapPercent)
+	}
+	if p.height > 0 {
+		msg += fmt.Sprintf(" (%s tall, %dpx top/bottom)", formatPercent(p.height), p.vertical.TopGapPixels)
+	}
+	return msg
+}
+
+// aligned reports whether the workspace is pinned to an edge.
+func (p layoutPlan) aligned() bool {
+	return p.align == gaps.AlignLeft || p.align == gaps.AlignRight
+}
+
+// layout returns the state layout the plan produces.
+func (p layoutPlan) layout() config.Layout {
+	layout := config.Layout{Current: p.percentage, Shift: p.shift, Size: p.size, Height: p.height}
+	if p.aligned() {
+		layout.Align, layout.Margin = string(p.align), p.margin
+	}
+	return layout
+}
+
+// appliedGaps returns the gaps the plan writes to the config.
+func (p layoutPlan) appliedGaps() config.AppliedGaps {
+	applied := config.AppliedGaps{Left: p.gaps.LeftGapPixels, Right: p.gaps.RightGapPixels}
+	if p.height > 0 || p.req.resetHeight {
+		applied.Top, applied.Bottom = &p.vertical.TopGapPixels, &p.vertical.BottomGapPixels
+	}
+	return applied
+}
+
+// errNoCurrent indicates a monitor has no current percentage to adjust or shift.
+var errNoCurrent = errors.New("no current percentage set; use 'workspace use' first")
+
+// errNoPercentage indicates a monitor has no explicit, current or default percentage.
+var errNoPercentage = errors.New("no percentage specified and no current/default set for this monitor")
+
+// applyLayouts updates the config and state for every requested monitor with
+// a single write of each file, then reloads aerospace once.
+func applyLayouts(opts *cli.GlobalOptions, out *output.Printer, reqs []layoutRequest) error {
+	configSvc, stateSvc := NewServices(opts)
+
+	plans := make([]layoutPlan, 0, len(reqs))
+	for _, req := range reqs {
+		plan, err := planLayout(opts, stateSvc, req)
+		if opts.All && errors.Is(err, errNoPercentage) {
+			out.Warning("Skipping %s: no current or default percentage\n", req.monitor)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		plans = append(plans, plan)
+	}
+	if len(plans) == 0 {
+		return errNoPercentage
+	}
+
+	if opts.DryRun {
+		if opts.Output.Structured() {
+			return encodeLayouts(opts, plans, ReloadResult{Status: ReloadSkipped, Message: "dry run"})
+		}
+		for _, plan := range plans {
+			out.DryRun()
+			out.Printf("Would set %s to %s %s\n",
+				plan.req.monitor, formatWidth(plan.percentage, plan.size), plan.gapMessage())
+		}
+		return nil
+	}
+
+	reload, err := commitLayouts(opts, configSvc, stateSvc, plans)
+	if err != nil {
+		return err
+	}
+	if opts.Output.Structured() {
+		return encodeLayouts(opts, plans, reload)
+	}
+
+	for _, plan := range plans {
+		defaultSuffix := ""
+		if plan.req.setDefault {
+			defaultSuffix = ", set as default"
+		}
+		out.Success("Set %s to %s %s%s%s\n",
+			plan.req.monitor, formatWidth(plan.percentage, plan.size), plan.gapMessage(), defaultSuffix, reload.Suffix())
+	}
+
+	return nil
+}
+
+// NewPrinter returns a printer for human-readable messages. With a structured
+// --output format they go to stderr so stdout only carries the document.
+func NewPrinter(opts *cli.GlobalOptions) *output.Printer {
+	out := output.New(opts.NoColor)
+	out.SetWriter(opts.Stdout)
+	if opts.Output.Structured() {
+		out.SetWriter(opts.Stderr)
+	}
+	return out
+}
+
+// NewServices creates the config and state services for the global options.
+func NewServices(opts *cli.GlobalOptions) (*config.AerospaceService, *config.WorkspaceService) {
+	configSvc := config.NewAerospaceService(opts.ConfigPath)
+	configSvc.SetCreateMissing(opts.Create)
+	configSvc.SetBackups(BackupsFor(opts))
+	stateSvc := config.NewWorkspaceService(opts.StatePath)
+	stateSvc.SetBackups(BackupsFor(opts))
+	if opts.BaseConfig != "" {
+		configSvc.SetOverlay(opts.BaseConfig, stateSvc)
+	}
+	return configSvc, stateSvc
+}
+
+// BackupsFor returns the backup settings for the global options.
+func BackupsFor(opts *cli.GlobalOptions) config.Backups {
+	return config.Backups{Dir: opts.BackupDir, Keep: opts.Backups}
+}
+
+// commitLayouts writes the planned gaps to the config and the layouts to the
+// state in one transaction, then reloads aerospace, rolling both files back
+// if the reload fails and --rollback-on-reload-failure is set. It records
+// whether each monitor's state changed in its plan and returns the reload
+// outcome.
+func commitLayouts(opts *cli.GlobalOptions, configSvc *config.AerospaceService, stateSvc *config.WorkspaceService, plans []layoutPlan) (ReloadResult, error) {
+	// Check if config exists
+	exists, err := configSvc.Exists()
+	if err != nil {
+		return ReloadResult{}, fmt.Errorf("check config: %w", err)
+	}
+	if !exists {
+		return ReloadResult{}, fmt.Errorf("config file not found: %s\nCreate it manually or run 'aerospace' to generate a default config", configSvc.SourcePath())
+	}
+
+	for i, plan := range plans {
+		var err error
+		if plan.shift == 0 && !plan.aligned() {
+			err = configSvc.SetMonitorGaps(plan.req.monitor, plan.gaps.LeftGapPixels)
+		} else {
+			err = configSvc.SetMonitorAsymmetricGaps(plan.req.monitor, plan.gaps.LeftGapPixels, plan.gaps.RightGapPixels)
+		}
+		if err != nil {
+			return ReloadResult{}, updateConfigError(err)
+		}
+
+		if plan.height == 0 && plan.req.resetHeight {
+			if plan.vertical, err = defaultVerticalGaps(configSvc); err != nil {
+				return ReloadResult{}, fmt.Errorf("load config: %w", err)
+			}
+			plans[i] = plan
+		}
+		if plan.height > 0 || plan.req.resetHeight {
+			err := configSvc.SetMonitorVerticalGaps(plan.req.monitor, plan.vertical.TopGapPixels, plan.vertical.BottomGapPixels)
+			if err != nil {
+				return ReloadResult{}, updateConfigError(err)
+			}
+		}
+
+		// Recorded before the config is written, which renders overlay
+		// configs from them.
+		if err := stateSvc.RecordGaps(plan.req.monitor, plan.appliedGaps()); err != nil {
+			return ReloadResult{}, fmt.Errorf("update state: %w", err)
+		}
+	}
+
+	// Update state, recording each change in the monitor's history
+	for i, plan := range plans {
+		monState, err := stateSvc.GetMonitorState(plan.req.monitor)
+		if err != nil {
+			return ReloadResult{}, fmt.Errorf("load state: %w", err)
+		}
+		before := storedLayoutOf(monState)
+
+		// Without --height the stored height is kept.
+		layout := plan.layout()
+		if plan.height == 0 && !plan.req.resetHeight {
+			layout.Height = valueOrZero(monState.Height)
+		}
+
+		switch plan.req.history {
+		case historyUndo:
+			_, err = stateSvc.Undo(plan.req.monitor)
+		case historyRedo:
+			_, err = stateSvc.Redo(plan.req.monitor)
+		case historyNone:
+			err = stateSvc.ReplaceLayout(plan.req.monitor, layout)
+		default:
+			err = stateSvc.SetLayout(plan.req.monitor, layout, plan.req.setDefault)
+		}
+		if err != nil {
+			return ReloadResult{}, fmt.Errorf("update state: %w", err)
+		}
+		plans[i].stateChanged = storedLayoutOf(monState) != before
+	}
+
+	tx := config.NewTransaction(configSvc, stateSvc)
+	if err := tx.Commit(); err != nil {
+		return ReloadResult{}, err
+	}
+
+	reload := ReloadAerospace(opts)
+	if reload.Status == ReloadFailed && opts.Rollback {
+		if err := tx.Rollback(); err != nil {
+			return ReloadResult{}, fmt.Errorf("reload failed: %s; roll back: %w", reload.Message, err)
+		}
+		return ReloadResult{}, fmt.Errorf("reload failed, previous config and state restored: %s", reload.Message)
+	}
+	return reload, nil
+}
+
+// targetMonitors returns the monitors a command should act on: the --monitor
+// value (resolving "focused"), or with --all every monitor in the config. With --create, detected
+// displays that have no config entry yet are included as well.
+func targetMonitors(opts *cli.GlobalOptions) ([]string, error) {
+	if !opts.All {
+		if err := resolveFocusedMonitor(opts); err != nil {
+			return nil, err
+		}
+		return []string{opts.Monitor}, nil
+	}
+
+	configSvc, _ := NewServices(opts)
+	names, err := configSvc.MonitorNames()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+
+	if opts.Create && display.Available() {
+		displays, err := display.Enumerate()
+		if err != nil {
+			return nil, fmt.Errorf("enumerate displays: %w", err)
+		}
+		for _, d := range displays {
+			if !displayCovered(d, names) {
+				names = append(names, d.Name)
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, errors.New("no monitors found in config; use --create to add detected displays")
+	}
+	return names, nil
+}
+
+// displayCovered reports whether a config monitor name already targets d.
+func displayCovered(d display.Info, names []string) bool {
+	for _, name := range names {
+		if strings.EqualFold(name, d.Name) || (name == "main" && d.Main) {
+			return true
+		}
+	}
+	return false
+}
+
+// planLayout resolves the percentage, shift and gaps for a request.
+func planLayout(opts *cli.GlobalOptions, stateSvc *config.WorkspaceService, req layoutRequest) (layoutPlan, error) {
+	size, err := resolveSize(stateSvc, req)
+	if err != nil {
+		return layoutPlan{}, err
+	}
+
+	// Get monitor width
+	monitorWidth, err := resolveMonitorWidth(opts, req.monitor, req.displays)
+	if err != nil {
+		return layoutPlan{}, err
+	}
+
+	align, margin, err := resolveAlignment(stateSvc, req)
+	if err != nil {
+		return layoutPlan{}, err
+	}
+
+	// Use the requested shift, or keep the existing one. A requested
+	// alignment, center included, anchors the workspace anew and drops the
+	// stored shift; aligned workspaces are placed by their edge instead.
+	shift := int64(0)
+	switch {
+	case req.shift != nil:
+		shift = *req.shift
+	case req.align != nil:
+	default:
+		if shift, err = stateSvc.GetShift(req.monitor); err != nil {
+			return layoutPlan{}, fmt.Errorf("load shift: %w", err)
+		}
+	}
+	if align != gaps.AlignCenter {
+		shift = 0
+	}
+
+	plan, err := planSize(opts, req, size, monitorWidth, shift)
+	if errors.Is(err, gaps.ErrInvalidShift) {
+		// Shift is no longer valid for the current size.
+		plan, err = planSize(opts, req, size, monitorWidth, 0)
+	}
+	if err != nil {
+		return layoutPlan{}, err
+	}
+
+	plan.align = align
+	if align != gaps.AlignCenter {
+		plan.margin = margin
+		plan.gaps, err = gaps.CalculateAlignedGaps(monitorWidth, plan.gaps.WorkspacePixels, margin, align, opts.Rounding)
+		if err != nil {
+			return layoutPlan{}, fmt.Errorf("align %s with %dpx margin: %w", align, margin, err)
+		}
+	}
+
+	if req.height != nil {
+		if err := gaps.ValidatePercentage(*req.height); err != nil {
+			return layoutPlan{}, fmt.Errorf("invalid height: %w", err)
+		}
+		monitorHeight, err := resolveMonitorHeight(opts, req.monitor, req.displays)
+		if err != nil {
+			return layoutPlan{}, err
+		}
+		plan.height = *req.height
+		plan.vertical = gaps.CalculateVerticalGaps(monitorHeight, plan.height, opts.Rounding)
+	}
+
+	return plan, nil
+}
+
+// defaultVerticalGaps returns the top and bottom gaps the config gives
+// monitors without an entry of their own.
+func defaultVerticalGaps(configSvc *config.AerospaceService) (gaps.VerticalGaps, error) {
+	summary, err := configSvc.Summary()
+	if err != nil {
+		return gaps.VerticalGaps{}, err
+	}
+	return gaps.VerticalGaps{TopGapPixels: valueOrZero(summary.OuterTop), BottomGapPixels: valueOrZero(summary.OuterBottom)}, nil
+}
+
+// resolveAlignment returns the edge and margin for a request: the requested
+// alignment, else the stored one. A requested margin replaces the stored
+// margin; a requested alignment without one is flush against the edge.
+func resolveAlignment(stateSvc *config.WorkspaceService, req layoutRequest) (gaps.Alignment, int64, error) {
+	if req.align != nil {
+		return *req.align, valueOrZero(req.margin), nil
+	}
+
+	stored, margin, err := stateSvc.GetAlignment(req.monitor)
+	if err != nil {
+		return "", 0, fmt.Errorf("load state: %w", err)
+	}
+	align, err := gaps.ParseAlignment(stored)
+	if err != nil {
+		// An unknown stored alignment falls back to centered.
+		align = gaps.AlignCenter
+	}
+	if req.margin != nil {
+		margin = *req.margin
+	}
+	return align, margin, nil
+}
+
+// layoutRequestFor returns a request that re-applies a stored layout, as used
+// by undo, redo and presets.
+func layoutRequestFor(monitor string, layout config.Layout) layoutRequest {
+	align, err := gaps.ParseAlignment(layout.Align)
+	if err != nil {
+		align = gaps.AlignCenter
+	}
+	var height *float64
+	if layout.Height > 0 {
+		height = &layout.Height
+	}
+	return layoutRequest{
+		monitor:    monitor,
+		percentage: &layout.Current,
+		shift:      &layout.Shift,
+		size:       storedSize(layout.Size),
+		align:      &align,
+		margin:     &layout.Margin,
+		height:     height,
+	}
+}
+
+// resolveSize returns the workspace size for a request: the requested size
+// or percentage, else the stored absolute size, else the current or default
+// percentage.
+func resolveSize(stateSvc *config.WorkspaceService, req layoutRequest) (gaps.Size, error) {
+	if req.size != nil {
+		return *req.size, nil
+	}
+
+	if req.percentage == nil {
+		stored, err := stateSvc.GetSize(req.monitor)
+		if err != nil {
+			return gaps.Size{}, fmt.Errorf("load state: %w", err)
+		}
+		if size := storedSize(stored); size != nil {
+			return *size, nil
+		}
+	}
+
+	// Resolve percentage
+	percentage, err := stateSvc.ResolvePercentage(req.monitor, req.percentage)
+	if err != nil {
+		return gaps.Size{}, fmt.Errorf("load state: %w", err)
+	}
+	if percentage == nil {
+		return gaps.Size{}, errNoPercentage
+	}
+
+	// Validate percentage
+	if err := gaps.ValidatePercentage(*percentage); err != nil {
+		return gaps.Size{}, err
+	}
+	return gaps.Size{Value: *percentage, Unit: gaps.UnitPercent}, nil
+}
+
+// planSize calculates the gaps for a size and shift. It returns
+// gaps.ErrInvalidShift when the shift does not fit.
+func planSize(opts *cli.GlobalOptions, req layoutRequest, size gaps.Size, monitorWidth, shift int64) (layoutPlan, error) {
+	plan := layoutPlan{req: req, width: monitorWidth, shift: shift}
+
+	if size.Unit == gaps.UnitPercent {
+		plan.percentage = size.Value
+		if shift != 0 {
+			if err := gaps.ValidateShift(monitorWidth, plan.percentage, shift, opts.Rounding); err != nil {
+				return layoutPlan{}, err
+			}
+		}
+		plan.gaps = gaps.CalculateShiftedGaps(monitorWidth, plan.percentage, shift, opts.Rounding)
+		return plan, nil
+	}
+
+	// Absolute sizes are kept so they re-apply correctly on other monitors.
+	workspaceWidth, err := sizePixels(opts, req.monitor, size, monitorWidth, req.displays)
+	if err != nil {
+		return layoutPlan{}, err
+	}
+	if err := gaps.ValidateShiftForWidth(monitorWidth, workspaceWidth, shift, opts.Rounding); err != nil {
+		return layoutPlan{}, err
+	}
+
+	plan.size = size.String()
+	plan.percentage = gaps.PercentageOf(monitorWidth, workspaceWidth)
+	plan.gaps = gaps.CalculateShiftedGapsForWidth(monitorWidth, workspaceWidth, shift, opts.Rounding)
+	return plan, nil
+}
+
+// storedSize parses an absolute size saved in the state file, returning nil
+// for percentages so the request falls back to its percentage.
+func storedSize(s string) *gaps.Size {
+	if s == "" {
+		return nil
+	}
+	size, err := gaps.ParseSize(s)
+	if err != nil {
+		return nil
+	}
+	return &size
+}
+
+// sizePixels converts an absolute size to pixels on a monitor. Sizes in cm
+// need the monitor's physical width from display detection, or from
+// displays when it is not nil.
+func sizePixels(opts *cli.GlobalOptions, monitor string, size gaps.Size, monitorWidth int64, displays []display.Info) (int64, error) {
+	var widthMM int64
+	if size.Unit == gaps.UnitCentimeters {
+		d, err := resolveDisplay(monitor, cli.FlagMonitorWidth, displays)
+		if err != nil {
+			return 0, err
+		}
+		widthMM = d.WidthMM
+	}
+
+	pixels, err := size.Pixels(monitorWidth, widthMM, opts.Rounding)
+	if errors.Is(err, gaps.ErrPhysicalSizeUnknown) {
+		return 0, fmt.Errorf("cannot use %s on %s: %w", size, monitor, err)
+	}
+	return pixels, err
+}
+
+// storedLayout is the part of a monitor's state that a layout change updates.
+type storedLayout struct {
+	layout                 config.Layout
+	defaultPct             float64
+	hasCurrent, hasDefault bool
+}
+
+func storedLayoutOf(mon *config.MonitorState) storedLayout {
+	return storedLayout{
+		layout:     mon.Layout(),
+		defaultPct: valueOrZero(mon.Default),
+		hasCurrent: mon.Current != nil,
+		hasDefault: mon.Default != nil,
+	}
+}
+
+// ReloadStatus is the outcome of reloading aerospace after a change.
+type ReloadStatus string
+
+const (
+	ReloadOK       ReloadStatus = "ok"
+	ReloadSkipped  ReloadStatus = "skipped"
+	ReloadNotFound ReloadStatus = "not-found"
+	ReloadFailed   ReloadStatus = "failed"
+)
+
+// ReloadResult reports how the aerospace reload went.
+type ReloadResult struct {
+	Status  ReloadStatus `json:"status"`
+	Message string       `json:"message,omitempty"`
+}
+
+// Suffix returns the reload status suffix for success messages.
+func (r ReloadResult) Suffix() string {
+	switch r.Status {
+	case ReloadSkipped:
+		return " (reload skipped)"
+	case ReloadNotFound:
+		return " (aerospace not found)"
+	case ReloadFailed:
+		return fmt.Sprintf(" (reload failed: %s)", r.Message)
+	}
+	return ""
+}
+
+// ReloadAerospace reloads the aerospace config over its socket, or by running
+// `aerospace reload-config`, unless disabled.
+func ReloadAerospace(opts *cli.GlobalOptions) ReloadResult {
+	if opts.NoReload {
+		return ReloadResult{Status: ReloadSkipped, Message: "--no-reload"}
+	}
+
+	err := aerospace.NewClient().ReloadConfig()
+	if errors.Is(err, aerospace.ErrNotFound) {
+		return ReloadResult{Status: ReloadNotFound, Message: aerospace.ErrNotFound.Error()}
+	}
+	if err != nil {
+		return ReloadResult{Status: ReloadFailed, Message: err.Error()}
+	}
+	return ReloadResult{Status: ReloadOK}
+}
+
+// layoutResult is the structured result for one monitor.
+type layoutResult struct {
+	Monitor         string  `json:"monitor"`
+	Width           int64   `json:"width"`
+	Percentage      float64 `json:"percentage"`
+	Size            string  `json:"size,omitempty"`
+	Shift           int64   `json:"shift"`
+	Align           string  `json:"align,omitempty"`
+	Margin          int64   `json:"margin,omitempty"`
+	LeftGapPixels   int64   `json:"left_gap_px"`
+	LeftGapPercent  int64   `json:"left_gap_percent"`
+	RightGapPixels  int64   `json:"right_gap_px"`
+	RightGapPercent int64   `json:"right_gap_percent"`
+	WorkspacePixels int64   `json:"workspace_px"`
+	SetDefault      bool    `json:"set_default"`
+	StateChanged    bool    `json:"state_changed"`
+
+	Vertical *verticalResult `json:"vertical,omitempty"`
+}
+
+// verticalResult is the structured vertical layout, present with --height.
+type verticalResult struct {
+	Height          float64 `json:"height"`
+	TopGapPixels    int64   `json:"top_gap_px"`
+	BottomGapPixels int64   `json:"bottom_gap_px"`
+}
+
+// applyResult is the structured result of a layout change.
+type applyResult struct {
+	DryRun   bool           `json:"dry_run"`
+	Wrote    bool           `json:"wrote"`
+	Reload   ReloadResult   `json:"reload"`
+	Monitors []layoutResult `json:"monitors"`
+}
+
+// encodeLayouts writes the structured result of applying plans.
+func encodeLayouts(opts *cli.GlobalOptions, plans []layoutPlan, reload ReloadResult) error {
+	result := applyResult{
+		DryRun:   opts.DryRun,
+		Wrote:    !opts.DryRun,
+		Reload:   reload,
+		Monitors: make([]layoutResult, 0, len(plans)),
+	}
+	for _, plan := range plans {
+		var vertical *verticalResult
+		if plan.height > 0 {
+			vertical = &verticalResult{
+				Height:          plan.height,
+				TopGapPixels:    plan.vertical.TopGapPixels,
+				BottomGapPixels: plan.vertical.BottomGapPixels,
+			}
+		}
+		result.Monitors = append(result.Monitors, layoutResult{
+			Monitor:         plan.req.monitor,
+			Width:           plan.width,
+			Percentage:      plan.percentage,
+			Size:            plan.size,
+			Shift:           plan.shift,
+			Align:           plan.layout().Align,
+			Margin:          plan.layout().Margin,
+			LeftGapPixels:   plan.gaps.LeftGapPixels,
+			LeftGapPercent:  plan.gaps.LeftGapPercent,
+			RightGapPixels:  plan.gaps.RightGapPixels,
+			RightGapPercent: plan.gaps.RightGapPercent,
+			WorkspacePixels: plan.gaps.WorkspacePixels,
+			SetDefault:      plan.req.setDefault,
+			StateChanged:    plan.stateChanged,
+			Vertical:        vertical,
+		})
+	}
+	return output.EncodeTo(opts.Stdout, opts.Output, result)
+}
@@ -0,0 +1,209 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/mholtzscher/aerospace-utils/internal/cli"
+	"github.com/mholtzscher/aerospace-utils/internal/config"
+	"github.com/mholtzscher/aerospace-utils/internal/output"
+	ufcli "github.com/urfave/cli/v3"
+)
+
+func newCheckCommand() *ufcli.Command {
+	return &ufcli.Command{
+		Name:  "check",
+		Usage: "Check that aerospace.toml still matches the saved layouts",
+		Description: `For every monitor with a saved layout, calculate the gaps the layout gives
+on the monitor's current width and compare them with the gaps in
+aerospace.toml. Exits non-zero and lists the differences when any monitor
+has drifted, for example after the config was edited by hand or
+overwritten by home-manager.
+
+Monitors whose width cannot be detected are reported but do not fail the
+check. Use --monitor to check a single monitor.
+
+To fix drift, rewrite the config from the saved layouts with
+'workspace use', or save the config's gaps as the layouts with
+'workspace sync'.
+
+Examples:
+  aerospace-utils workspace check
+  aerospace-utils workspace check --monitor main
+  aerospace-utils workspace check --output json`,
+		Action: func(ctx context.Context, cmd *ufcli.Command) error {
+			return runCheck(cmd)
+		},
+	}
+}
+
+// errDrift indicates aerospace.toml no longer matches the saved layouts.
+var errDrift = errors.New("aerospace.toml does not match the saved layouts")
+
+// driftStatus is the outcome of checking one monitor.
+type driftStatus string
+
+const (
+	driftOK      driftStatus = "ok"
+	driftDrifted driftStatus = "drifted"
+	driftUnknown driftStatus = "unknown"
+)
+
+// gapDrift is a gap whose value in aerospace.toml differs from the one the
+// saved layout gives.
+type gapDrift struct {
+	Gap      string `json:"gap"` // left, right, top or bottom
+	Expected int64  `json:"expected"`
+	Actual   *int64 `json:"actual"` // nil when missing from aerospace.toml
+}
+
+func (d gapDrift) String() string {
+	if d.Actual == nil {
+		return fmt.Sprintf("%s: expected %dpx, missing from aerospace.toml", d.Gap, d.Expected)
+	}
+	return fmt.Sprintf("%s: expected %dpx, aerospace.toml has %dpx", d.Gap, d.Expected, *d.Actual)
+}
+
+// driftResult is the structured result for one monitor.
+type driftResult struct {
+	Monitor  string      `json:"monitor"`
+	Status   driftStatus `json:"status"`
+	Width    int64       `json:"width,omitempty"`
+	LeftGap  int64       `json:"left_gap,omitempty"`
+	RightGap int64       `json:"right_gap,omitempty"`
+	Drift    []gapDrift  `json:"drift"`
+	Message  string      `json:"message,omitempty"`
+}
+
+func runCheck(cmd *ufcli.Command) error {
+	opts := cli.GetOptions(cmd)
+	out := NewPrinter(opts)
+
+	var only string
+	if cmd.Root().IsSet(cli.FlagMonitor) {
+		if err := resolveFocusedMonitor(opts); err != nil {
+			return err
+		}
+		only = opts.Monitor
+	}
+
+	configSvc, stateSvc := NewServices(opts)
+	summary, err := configSvc.Summary()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	monitors, err := stateSvc.Monitors()
+	if err != nil {
+		return fmt.Errorf("load state: %w", err)
+	}
+	if only != "" {
+		if _, ok := monitors[only]; !ok {
+			return fmt.Errorf("no saved layout for %s", only)
+		}
+		monitors = map[string]*config.MonitorState{only: monitors[only]}
+	}
+
+	results := checkDrift(opts, stateSvc, summary, monitors)
+
+	var drifted []string
+	for _, r := range results {
+		if r.Status == driftDrifted {
+			drifted = append(drifted, r.Monitor)
+		}
+	}
+
+	if opts.Output.Structured() {
+		err := output.EncodeTo(opts.Stdout, opts.Output, struct {
+			Drifted  bool          `json:"drifted"`
+			Monitors []driftResult `json:"monitors"`
+		}{len(drifted) > 0, results})
+		if err != nil {
+			return err
+		}
+	} else {
+		printDriftResults(out, results)
+	}
+
+	if len(drifted) > 0 {
+		return fmt.Errorf("%w on %s", errDrift, strings.Join(drifted, ", "))
+	}
+	return nil
+}
+
+// checkDrift compares the gaps each monitor's saved layout gives with the
+// gaps in the config. Monitors without a current layout are left out.
+func checkDrift(opts *cli.GlobalOptions, stateSvc *config.WorkspaceService, summary config.Summary, monitors map[string]*config.MonitorState) []driftResult {
+	left, right := gapsByMonitor(summary.LeftGaps), gapsByMonitor(summary.RightGaps)
+	top, bottom := gapsByMonitor(summary.TopGaps), gapsByMonitor(summary.BottomGaps)
+
+	results := []driftResult{}
+	for _, name := range slices.Sorted(maps.Keys(monitors)) {
+		mon := monitors[name]
+		if mon.Current == nil {
+			continue
+		}
+
+		result := driftResult{Monitor: name, Status: driftUnknown, Drift: []gapDrift{}}
+		plan, err := planLayout(opts, stateSvc, layoutRequest{monitor: name, percentage: mon.Current, size: storedSize(mon.Size), height: mon.Height})
+		if err != nil {
+			result.Message = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		result.Width = plan.width
+		result.LeftGap, result.RightGap = plan.gaps.LeftGapPixels, plan.gaps.RightGapPixels
+		result.Drift = appendDrift(result.Drift, "left", plan.gaps.LeftGapPixels, left, name)
+		result.Drift = appendDrift(result.Drift, "right", plan.gaps.RightGapPixels, right, name)
+		if plan.height > 0 {
+			result.Drift = appendDrift(result.Drift, "top", plan.vertical.TopGapPixels, top, name)
+			result.Drift = appendDrift(result.Drift, "bottom", plan.vertical.BottomGapPixels, bottom, name)
+		}
+
+		result.Status = driftOK
+		if len(result.Drift) > 0 {
+			result.Status = driftDrifted
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// appendDrift adds a gapDrift to drift when the configured gap differs from
+// expected.
+func appendDrift(drift []gapDrift, gap string, expected int64, configured map[string]int64, monitor string) []gapDrift {
+	actual, ok := configured[monitor]
+	if ok && actual == expected {
+		return drift
+	}
+	d := gapDrift{Gap: gap, Expected: expected}
+	if ok {
+		d.Actual = &actual
+	}
+	return append(drift, d)
+}
+
+func printDriftResults(out *output.Printer, results []driftResult) {
+	if len(results) == 0 {
+		out.Unset("No saved layouts to check\n")
+		return
+	}
+
+	for _, r := range results {
+		switch r.Status {
+		case driftOK:
+			out.Success("%s matches aerospace.toml (left: %dpx, right: %dpx)\n", r.Monitor, r.LeftGap, r.RightGap)
+		case driftDrifted:
+			out.Error("%s has drifted:\n", r.Monitor)
+			for _, d := range r.Drift {
+				out.Printf("  %s\n", d)
+			}
+		default:
+			out.Warning("Cannot check %s: %s\n", r.Monitor, r.Message)
+		}
+	}
+}
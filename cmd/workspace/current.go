@@ -3,9 +3,13 @@ package workspace
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/mholtzscher/aerospace-utils/internal/cli"
 	"github.com/mholtzscher/aerospace-utils/internal/config"
+	"github.com/mholtzscher/aerospace-utils/internal/display"
 	"github.com/mholtzscher/aerospace-utils/internal/output"
 	ufcli "github.com/urfave/cli/v3"
 )
@@ -18,7 +22,14 @@ func newCurrentCommand() *ufcli.Command {
 
 Shows:
 - Config file path and gap values
-- State file path and per-monitor percentages`,
+- State file path and per-monitor percentages
+
+With --output json or --output yaml, prints a machine-readable report that
+also includes detected displays and the computed gaps for each monitor.
+
+Examples:
+  aerospace-utils workspace current
+  aerospace-utils workspace current --output json`,
 		Action: func(ctx context.Context, cmd *ufcli.Command) error {
 			return runCurrent(cmd)
 		},
@@ -28,29 +39,38 @@ Shows:
 func runCurrent(cmd *ufcli.Command) error {
 	opts := cli.GetOptions(cmd)
 	out := output.New(opts.NoColor)
+	out.SetWriter(opts.Stdout)
 
-	configSvc := config.NewAerospaceService(opts.ConfigPath)
-	stateSvc := config.NewWorkspaceService(opts.StatePath)
+	configSvc, stateSvc := NewServices(opts)
+
+	if opts.Output.Structured() {
+		return output.EncodeTo(opts.Stdout, opts.Output, buildCurrentReport(opts, configSvc, stateSvc))
+	}
 
 	// Print config info
 	out.PrintHeader("Config")
 	out.PrintPath("path", configSvc.ConfigPath())
+	if base := configSvc.BasePath(); base != "" {
+		out.PrintPath("base", base)
+	}
 
+	var summary *config.Summary
 	exists, err := configSvc.Exists()
 	if err != nil {
 		out.Error("  Error checking config: %v\n", err)
 	} else if exists {
-		summary, err := configSvc.Summary()
+		loaded, err := configSvc.Summary()
 		if err != nil {
 			out.Error("  Error loading config: %v\n", err)
 		} else {
-			printConfigSummary(out, summary)
+			summary = &loaded
+			printConfigSummary(out, loaded)
 		}
 	} else {
 		out.Unset("  (file not found)\n")
 	}
 
-	fmt.Println()
+	out.Printf("\n")
 
 	// Print state info
 	out.PrintHeader("State")
@@ -64,7 +84,7 @@ func runCurrent(cmd *ufcli.Command) error {
 		if err != nil {
 			out.Error("  Error loading state: %v\n", err)
 		} else {
-			printMonitorsSummary(out, monitors)
+			printMonitorsSummary(out, monitors, driftByMonitor(opts, stateSvc, summary, monitors))
 		}
 	} else {
 		out.Unset("  (file not found)\n")
@@ -75,54 +95,289 @@ func runCurrent(cmd *ufcli.Command) error {
 
 func printConfigSummary(out *output.Printer, s config.Summary) {
 	out.Label("  Inner gaps:\n")
-	out.PrintKeyValue("horizontal", formatOptionalInt(s.InnerHorizontal))
-	out.PrintKeyValue("vertical", formatOptionalInt(s.InnerVertical))
+	out.PrintKeyValue("horizontal", formatOptional(s.InnerHorizontal))
+	out.PrintKeyValue("vertical", formatOptional(s.InnerVertical))
 
 	out.Label("  Outer gaps:\n")
-	out.PrintKeyValue("top", formatOptionalInt(s.OuterTop))
-	out.PrintKeyValue("bottom", formatOptionalInt(s.OuterBottom))
+	out.PrintKeyValue("top", formatOptional(s.OuterTop))
+	out.PrintKeyValue("bottom", formatOptional(s.OuterBottom))
 
-	if len(s.LeftGaps) > 0 {
-		out.Label("  Left (per-monitor):\n")
-		for _, g := range s.LeftGaps {
-			out.Printf("    ")
-			out.Label("%s: ", g.Name)
-			out.Value("%d\n", g.Value)
-		}
-	}
+	printMonitorGaps(out, "Left", s.LeftGaps)
+	printMonitorGaps(out, "Right", s.RightGaps)
+	printMonitorGaps(out, "Top", s.TopGaps)
+	printMonitorGaps(out, "Bottom", s.BottomGaps)
+}
 
-	if len(s.RightGaps) > 0 {
-		out.Label("  Right (per-monitor):\n")
-		for _, g := range s.RightGaps {
-			out.Printf("    ")
-			out.Label("%s: ", g.Name)
-			out.Value("%d\n", g.Value)
-		}
+// printMonitorGaps prints the per-monitor gaps of one side, if any.
+func printMonitorGaps(out *output.Printer, side string, monitorGaps []config.MonitorGap) {
+	if len(monitorGaps) == 0 {
+		return
+	}
+	out.Label("  %s (per-monitor):\n", side)
+	for _, g := range monitorGaps {
+		out.Printf("    ")
+		out.Label("%s: ", g.Name)
+		out.Value("%d\n", g.Value)
 	}
 }
 
-func printMonitorsSummary(out *output.Printer, monitors map[string]*config.MonitorState) {
+func printMonitorsSummary(out *output.Printer, monitors map[string]*config.MonitorState, drift map[string]driftResult) {
 	if len(monitors) == 0 {
 		out.Unset("  (no monitors configured)\n")
 		return
 	}
 
-	for name, mon := range monitors {
+	for _, name := range slices.Sorted(maps.Keys(monitors)) {
+		mon := monitors[name]
 		out.Label("  %s:\n", name)
 		out.Printf("    ")
-		out.PrintKeyValue("current", formatOptionalInt(mon.Current))
+		out.PrintKeyValue("current", formatOptional(mon.Current))
 		out.Printf("    ")
-		out.PrintKeyValue("default", formatOptionalInt(mon.Default))
+		out.PrintKeyValue("default", formatOptional(mon.Default))
+		if mon.Size != "" {
+			out.Printf("    ")
+			out.PrintKeyValue("size", mon.Size)
+		}
+		if mon.Align != "" {
+			out.Printf("    ")
+			out.PrintKeyValue("align", formatAlign(mon.Align, mon.Margin))
+		}
 		if mon.Shift != nil && *mon.Shift != 0 {
 			out.Printf("    ")
-			out.PrintKeyValue("shift", formatOptionalInt(mon.Shift))
+			out.PrintKeyValue("shift", formatOptional(mon.Shift))
 		}
+		if mon.Height != nil {
+			out.Printf("    ")
+			out.PrintKeyValue("height", formatOptional(mon.Height))
+		}
+		if r, ok := drift[name]; ok && r.Status == driftDrifted {
+			out.Printf("    ")
+			out.Error("drifted: ")
+			out.Printf("%s\n", joinDrift(r.Drift))
+		}
+	}
+}
+
+// driftByMonitor checks the monitors against the config summary, returning
+// nothing when the config could not be loaded.
+func driftByMonitor(opts *cli.GlobalOptions, stateSvc *config.WorkspaceService, summary *config.Summary, monitors map[string]*config.MonitorState) map[string]driftResult {
+	drift := make(map[string]driftResult)
+	if summary == nil {
+		return drift
+	}
+	for _, r := range checkDrift(opts, stateSvc, *summary, monitors) {
+		drift[r.Monitor] = r
+	}
+	return drift
+}
+
+// joinDrift describes drifted gaps on one line.
+func joinDrift(drift []gapDrift) string {
+	parts := make([]string, 0, len(drift))
+	for _, d := range drift {
+		parts = append(parts, d.String())
+	}
+	return strings.Join(parts, "; ")
+}
+
+// formatAlign describes an alignment, e.g. "left" or "left (20px margin)".
+func formatAlign(align string, margin int64) string {
+	if margin != 0 {
+		return fmt.Sprintf("%s (%dpx margin)", align, margin)
 	}
+	return align
 }
 
-func formatOptionalInt(v *int64) any {
+func formatOptional[T int64 | float64](v *T) any {
 	if v == nil {
 		return nil
 	}
 	return *v
 }
+
+// currentReport is the structured form of `workspace current`.
+type currentReport struct {
+	Config   configReport    `json:"config"`
+	State    stateReport     `json:"state"`
+	Displays []displayReport `json:"displays"`
+	Layouts  []layoutReport  `json:"layouts"`
+}
+
+type configReport struct {
+	Path   string `json:"path"`
+	Base   string `json:"base,omitempty"` // read-only config rendered to Path
+	Exists bool   `json:"exists"`
+	Error  string `json:"error,omitempty"`
+	Inner  struct {
+		Horizontal *int64 `json:"horizontal"`
+		Vertical   *int64 `json:"vertical"`
+	} `json:"inner"`
+	Outer struct {
+		Top    *int64             `json:"top"`
+		Bottom *int64             `json:"bottom"`
+		Left   []monitorGapReport `json:"left"`
+		Right  []monitorGapReport `json:"right"`
+		// Per-monitor top/bottom entries; the scalar defaults are Top/Bottom.
+		TopMonitors    []monitorGapReport `json:"top_monitors"`
+		BottomMonitors []monitorGapReport `json:"bottom_monitors"`
+	} `json:"outer"`
+}
+
+type monitorGapReport struct {
+	Monitor string `json:"monitor"`
+	Value   int64  `json:"value"`
+}
+
+type stateReport struct {
+	Path     string          `json:"path"`
+	Exists   bool            `json:"exists"`
+	Error    string          `json:"error,omitempty"`
+	Monitors []monitorReport `json:"monitors"`
+}
+
+type monitorReport struct {
+	Name    string   `json:"name"`
+	Current *float64 `json:"current"`
+	Default *float64 `json:"default"`
+	Size    string   `json:"size,omitempty"`
+	Shift   int64    `json:"shift"`
+	Align   string   `json:"align,omitempty"`
+	Margin  int64    `json:"margin,omitempty"`
+	Height  *float64 `json:"height,omitempty"`
+}
+
+type displayReport struct {
+	Name   string `json:"name"`
+	Width  int64  `json:"width"`
+	Height int64  `json:"height"`
+	Main   bool   `json:"main"`
+}
+
+// layoutReport is the computed gaps for a monitor's current percentage.
+type layoutReport struct {
+	Monitor    string  `json:"monitor"`
+	Width      int64   `json:"width,omitempty"`
+	Percentage float64 `json:"percentage"`
+	Size       string  `json:"size,omitempty"`
+	Shift      int64   `json:"shift"`
+	LeftGap    int64   `json:"left_gap"`
+	RightGap   int64   `json:"right_gap"`
+	Drifted    bool    `json:"drifted"`
+	// Gaps in the config that differ from LeftGap/RightGap (and the
+	// vertical gaps when a height is saved).
+	Drift []gapDrift `json:"drift,omitempty"`
+	Error string     `json:"error,omitempty"`
+}
+
+// buildCurrentReport collects the config, state, displays and computed
+// layouts. Load errors are reported in the document rather than returned so
+// the output stays parseable.
+func buildCurrentReport(opts *cli.GlobalOptions, configSvc *config.AerospaceService, stateSvc *config.WorkspaceService) currentReport {
+	report := currentReport{
+		Displays: []displayReport{},
+		Layouts:  []layoutReport{},
+	}
+
+	report.Config.Path = configSvc.ConfigPath()
+	report.Config.Base = configSvc.BasePath()
+	report.Config.Outer.Left = []monitorGapReport{}
+	report.Config.Outer.Right = []monitorGapReport{}
+	report.Config.Outer.TopMonitors = []monitorGapReport{}
+	report.Config.Outer.BottomMonitors = []monitorGapReport{}
+	var summary *config.Summary
+	if exists, err := configSvc.Exists(); err != nil {
+		report.Config.Error = err.Error()
+	} else if exists {
+		report.Config.Exists = true
+		if loaded, err := configSvc.Summary(); err != nil {
+			report.Config.Error = err.Error()
+		} else {
+			summary = &loaded
+			report.Config.Inner.Horizontal = loaded.InnerHorizontal
+			report.Config.Inner.Vertical = loaded.InnerVertical
+			report.Config.Outer.Top = loaded.OuterTop
+			report.Config.Outer.Bottom = loaded.OuterBottom
+			report.Config.Outer.Left = monitorGapReports(loaded.LeftGaps)
+			report.Config.Outer.Right = monitorGapReports(loaded.RightGaps)
+			report.Config.Outer.TopMonitors = monitorGapReports(loaded.TopGaps)
+			report.Config.Outer.BottomMonitors = monitorGapReports(loaded.BottomGaps)
+		}
+	}
+
+	var monitors map[string]*config.MonitorState
+	report.State.Path = stateSvc.StatePath()
+	report.State.Monitors = []monitorReport{}
+	if exists, err := stateSvc.Exists(); err != nil {
+		report.State.Error = err.Error()
+	} else if exists {
+		report.State.Exists = true
+		if monitors, err = stateSvc.Monitors(); err != nil {
+			report.State.Error = err.Error()
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(monitors)) {
+		mon := monitors[name]
+		report.State.Monitors = append(report.State.Monitors, monitorReport{
+			Name:    name,
+			Current: mon.Current,
+			Default: mon.Default,
+			Size:    mon.Size,
+			Shift:   valueOrZero(mon.Shift),
+			Align:   mon.Align,
+			Margin:  mon.Margin,
+			Height:  mon.Height,
+		})
+	}
+
+	if display.Available() {
+		if displays, err := display.Enumerate(); err == nil {
+			for _, d := range displays {
+				report.Displays = append(report.Displays, displayReport{
+					Name:   d.Name,
+					Width:  d.Width,
+					Height: d.Height,
+					Main:   d.Main,
+				})
+			}
+		}
+	}
+
+	drift := driftByMonitor(opts, stateSvc, summary, monitors)
+	for _, mon := range report.State.Monitors {
+		if mon.Current == nil {
+			continue
+		}
+		plan, err := planLayout(opts, stateSvc, layoutRequest{monitor: mon.Name, percentage: mon.Current, size: storedSize(mon.Size)})
+		if err != nil {
+			report.Layouts = append(report.Layouts, layoutReport{
+				Monitor:    mon.Name,
+				Percentage: *mon.Current,
+				Size:       mon.Size,
+				Shift:      mon.Shift,
+				Error:      err.Error(),
+			})
+			continue
+		}
+		report.Layouts = append(report.Layouts, layoutReport{
+			Monitor:    mon.Name,
+			Width:      plan.width,
+			Percentage: plan.percentage,
+			Size:       plan.size,
+			Shift:      plan.shift,
+			LeftGap:    plan.gaps.LeftGapPixels,
+			RightGap:   plan.gaps.RightGapPixels,
+			Drifted:    drift[mon.Name].Status == driftDrifted,
+			Drift:      drift[mon.Name].Drift,
+		})
+	}
+
+	return report
+}
+
+func monitorGapReports(gaps []config.MonitorGap) []monitorGapReport {
+	reports := make([]monitorGapReport, 0, len(gaps))
+	for _, g := range gaps {
+		reports = append(reports, monitorGapReport{Monitor: g.Name, Value: g.Value})
+	}
+	return reports
+}
@@ -0,0 +1,84 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/mholtzscher/aerospace-utils/internal/cli"
+	ufcli "github.com/urfave/cli/v3"
+)
+
+const flagReverse = "reverse"
+
+func newCycleCommand() *ufcli.Command {
+	return &ufcli.Command{
+		Name:      "cycle",
+		Usage:     "Step through a list of workspace sizes",
+		ArgsUsage: "<percentage>...",
+		Description: `Set the workspace to the percentage after the current one in a list,
+wrapping around at the end. With --reverse, step backwards instead.
+
+If the current percentage is not in the list, the first value is used (the
+last with --reverse). The shift is kept, as with use.
+
+With --all, every monitor is set to the size picked for --monitor so they
+step together.
+
+Examples:
+  aerospace-utils workspace cycle 50 60 80 100
+  aerospace-utils workspace cycle 50 60 80 100 --reverse`,
+		Flags: []ufcli.Flag{
+			&ufcli.BoolFlag{
+				Name:  flagReverse,
+				Usage: "Step to the previous value instead of the next",
+			},
+		},
+		Action: func(ctx context.Context, cmd *ufcli.Command) error {
+			return WithLock(cli.GetOptions(cmd), func() error { return runCycle(cmd) })
+		},
+	}
+}
+
+func runCycle(cmd *ufcli.Command) error {
+	opts := cli.GetOptions(cmd)
+	out := NewPrinter(opts)
+
+	if cmd.Args().Len() == 0 {
+		return errors.New("cycle needs at least one percentage")
+	}
+	values, err := parsePercentages(cmd.Args().Slice())
+	if err != nil {
+		return err
+	}
+
+	current, err := currentPercentage(opts)
+	if err != nil {
+		return err
+	}
+
+	next := cycleNext(values, current, cmd.Bool(flagReverse))
+	return applyPercentage(cmd, opts, out, &next)
+}
+
+// cycleNext returns the value after current in values, wrapping around. When
+// current is unset or not in the list it returns the first value, or the last
+// when reversing.
+func cycleNext(values []float64, current *float64, reverse bool) float64 {
+	i := -1
+	if current != nil {
+		i = slices.Index(values, *current)
+	}
+
+	n := len(values)
+	switch {
+	case i < 0 && reverse:
+		return values[n-1]
+	case i < 0:
+		return values[0]
+	case reverse:
+		return values[(i-1+n)%n]
+	default:
+		return values[(i+1)%n]
+	}
+}
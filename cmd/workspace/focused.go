@@ -0,0 +1,73 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mholtzscher/aerospace-utils/internal/aerospace"
+	"github.com/mholtzscher/aerospace-utils/internal/cli"
+	"github.com/mholtzscher/aerospace-utils/internal/display"
+)
+
+// monitorFocused is the --monitor value that targets the focused monitor.
+const monitorFocused = "focused"
+
+// resolveFocusedMonitor replaces --monitor focused with the config entry for
+// the monitor aerospace reports as focused. Other values are left alone.
+func resolveFocusedMonitor(opts *cli.GlobalOptions) error {
+	if opts.Monitor != monitorFocused {
+		return nil
+	}
+
+	focused, err := aerospace.NewClient().FocusedMonitor()
+	if errors.Is(err, aerospace.ErrNotFound) {
+		return errors.New("cannot find the focused monitor: aerospace is not running or not in PATH")
+	}
+	if err != nil {
+		return fmt.Errorf("find focused monitor: %w", err)
+	}
+
+	// A missing config just means there are no entries to match yet.
+	configSvc, _ := NewServices(opts)
+	names, err := configSvc.MonitorNames()
+	if err != nil {
+		names = nil
+	}
+
+	opts.Monitor = configEntryFor(focused.Name, names)
+	return nil
+}
+
+// configEntryFor maps an aerospace monitor name to the config entry that
+// targets it: an entry with the same name, otherwise "main" or "secondary"
+// when detection says which one the monitor is, otherwise the name itself.
+func configEntryFor(name string, entries []string) string {
+	for _, entry := range entries {
+		if strings.EqualFold(entry, name) {
+			return entry
+		}
+	}
+
+	if !display.Available() {
+		return name
+	}
+	displays, err := display.Enumerate()
+	if err != nil {
+		return name
+	}
+
+	for _, d := range displays {
+		if !strings.EqualFold(d.Name, name) {
+			continue
+		}
+		if d.Main && slices.Contains(entries, "main") {
+			return "main"
+		}
+		if !d.Main && len(displays) == 2 && slices.Contains(entries, "secondary") {
+			return "secondary"
+		}
+	}
+	return name
+}
@@ -0,0 +1,118 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/mholtzscher/aerospace-utils/internal/cli"
+	"github.com/mholtzscher/aerospace-utils/internal/config"
+	"github.com/mholtzscher/aerospace-utils/internal/output"
+	ufcli "github.com/urfave/cli/v3"
+)
+
+const historyTimeFormat = "2006-01-02 15:04:05"
+
+func newHistoryCommand() *ufcli.Command {
+	return &ufcli.Command{
+		Name:  "history",
+		Usage: "List previous workspace layouts",
+		Description: `List the layouts recorded for a monitor, newest first.
+
+Each entry shows the percentage and shift that were active and when they
+were replaced. Use 'workspace undo' to step back through them.`,
+		Action: func(ctx context.Context, cmd *ufcli.Command) error {
+			return runHistory(cmd)
+		},
+	}
+}
+
+func runHistory(cmd *ufcli.Command) error {
+	opts := cli.GetOptions(cmd)
+	out := output.New(opts.NoColor)
+	out.SetWriter(opts.Stdout)
+
+	if err := resolveFocusedMonitor(opts); err != nil {
+		return err
+	}
+
+	_, stateSvc := NewServices(opts)
+	monState, err := stateSvc.GetMonitorState(opts.Monitor)
+	if err != nil {
+		return fmt.Errorf("load state: %w", err)
+	}
+
+	out.PrintHeader(fmt.Sprintf("History (%s)", opts.Monitor))
+	if monState.Current == nil {
+		out.PrintKeyValue("current", nil)
+	} else {
+		out.PrintKeyValue("current", formatLayout(monState.Layout()))
+	}
+
+	if len(monState.History) == 0 {
+		out.Unset("  (no history)\n")
+	}
+	for i := len(monState.History) - 1; i >= 0; i-- {
+		printHistoryEntry(out, fmt.Sprintf("-%d", len(monState.History)-i), monState.History[i])
+	}
+
+	if len(monState.Redo) > 0 {
+		out.PrintHeader("Redo")
+		for i := len(monState.Redo) - 1; i >= 0; i-- {
+			printHistoryEntry(out, fmt.Sprintf("+%d", len(monState.Redo)-i), monState.Redo[i])
+		}
+	}
+
+	return nil
+}
+
+func printHistoryEntry(out *output.Printer, label string, entry config.HistoryEntry) {
+	out.Label("  %s: ", label)
+	out.Value("%s", formatLayout(entry.Layout()))
+	if !entry.Time.IsZero() {
+		out.Path(" (replaced %s)", entry.Time.Local().Format(historyTimeFormat))
+	}
+	out.Printf("\n")
+}
+
+// formatLayout describes a layout, e.g. "60%, shifted 5% right",
+// "2560px (50%)", "60%, aligned left with 20px margin" or "60%, 80% tall".
+func formatLayout(layout config.Layout) string {
+	desc := formatWidth(layout.Current, layout.Size)
+	switch {
+	case layout.Align != "" && layout.Margin != 0:
+		desc = fmt.Sprintf("%s, aligned %s with %dpx margin", desc, layout.Align, layout.Margin)
+	case layout.Align != "":
+		desc = fmt.Sprintf("%s, aligned %s", desc, layout.Align)
+	case layout.Shift > 0:
+		desc = fmt.Sprintf("%s, shifted %d%% right", desc, layout.Shift)
+	case layout.Shift < 0:
+		desc = fmt.Sprintf("%s, shifted %d%% left", desc, -layout.Shift)
+	}
+	if layout.Height > 0 {
+		desc = fmt.Sprintf("%s, %s tall", desc, formatPercent(layout.Height))
+	}
+	return desc
+}
+
+// formatWidth describes a workspace width, e.g. "60%" or "2560px (50%)".
+func formatWidth(percentage float64, size string) string {
+	if size != "" {
+		return fmt.Sprintf("%s (%s)", size, formatPercent(percentage))
+	}
+	return formatPercent(percentage)
+}
+
+// formatPercent formats a percentage without trailing zeros, e.g. "60%" or
+// "62.5%".
+func formatPercent(percentage float64) string {
+	return strconv.FormatFloat(percentage, 'f', -1, 64) + "%"
+}
+
+// valueOrZero dereferences v, treating nil as 0.
+func valueOrZero[T int64 | float64](v *T) T {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
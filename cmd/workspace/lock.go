@@ -0,0 +1,46 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mholtzscher/aerospace-utils/internal/cli"
+	"github.com/mholtzscher/aerospace-utils/internal/config"
+)
+
+// conflictAttempts is how many times a command runs when aerospace.toml or
+// the state file keeps changing underneath it.
+const conflictAttempts = 3
+
+// WithLock runs a command that loads, changes and writes the config and
+// state files while holding the lock on each, so overlapping invocations,
+// such as from fast repeated keypresses, take turns instead of overwriting
+// each other's changes. When another program changed a file between load
+// and write, run is called again to start from the new content. Dry runs
+// write nothing and take no locks.
+func WithLock(opts *cli.GlobalOptions, run func() error) error {
+	if opts.DryRun {
+		return run()
+	}
+
+	// Always config then state, so two commands cannot each hold the lock
+	// the other is waiting for.
+	configSvc, stateSvc := NewServices(opts)
+	configLock, err := configSvc.Lock()
+	if err != nil {
+		return fmt.Errorf("lock config: %w", err)
+	}
+	defer func() { _ = configLock.Unlock() }()
+	stateLock, err := stateSvc.Lock()
+	if err != nil {
+		return fmt.Errorf("lock state: %w", err)
+	}
+	defer func() { _ = stateLock.Unlock() }()
+
+	for range conflictAttempts {
+		if err = run(); !errors.Is(err, config.ErrConflict) {
+			return err
+		}
+	}
+	return fmt.Errorf("%w (gave up after %d attempts)", err, conflictAttempts)
+}
@@ -0,0 +1,216 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/mholtzscher/aerospace-utils/internal/cli"
+	"github.com/mholtzscher/aerospace-utils/internal/config"
+	"github.com/mholtzscher/aerospace-utils/internal/output"
+	ufcli "github.com/urfave/cli/v3"
+)
+
+func newPresetCommand() *ufcli.Command {
+	return &ufcli.Command{
+		Name:  "preset",
+		Usage: "Save and apply named layouts",
+		Description: `Manage named layouts such as "coding" or "presenting".
+
+A preset stores the percentage and shift of one or more monitors in the
+state file. Applying it updates every monitor it covers with a single config
+write and reload.
+
+Examples:
+  aerospace-utils workspace preset save coding
+  aerospace-utils workspace preset save reading --monitor "Dell U2722D"
+  aerospace-utils workspace preset apply coding
+  aerospace-utils workspace preset list
+  aerospace-utils workspace preset delete reading`,
+		Commands: []*ufcli.Command{
+			{
+				Name:      "save",
+				Usage:     "Save the current layout as a preset",
+				ArgsUsage: "<name>",
+				Description: `Save the current percentage and shift of every monitor in the state file.
+With an explicit --monitor, only that monitor is saved.`,
+				Action: func(ctx context.Context, cmd *ufcli.Command) error {
+					return WithLock(cli.GetOptions(cmd), func() error { return runPresetSave(cmd) })
+				},
+			},
+			{
+				Name:      "apply",
+				Usage:     "Apply a saved preset",
+				ArgsUsage: "<name>",
+				Action: func(ctx context.Context, cmd *ufcli.Command) error {
+					return WithLock(cli.GetOptions(cmd), func() error { return runPresetApply(cmd) })
+				},
+			},
+			{
+				Name:  "list",
+				Usage: "List saved presets",
+				Action: func(ctx context.Context, cmd *ufcli.Command) error {
+					return runPresetList(cmd)
+				},
+			},
+			{
+				Name:      "delete",
+				Usage:     "Delete a saved preset",
+				ArgsUsage: "<name>",
+				Action: func(ctx context.Context, cmd *ufcli.Command) error {
+					return WithLock(cli.GetOptions(cmd), func() error { return runPresetDelete(cmd) })
+				},
+			},
+		},
+	}
+}
+
+// presetName returns the required preset name argument.
+func presetName(cmd *ufcli.Command) (string, error) {
+	if cmd.Args().Len() == 0 || cmd.Args().First() == "" {
+		return "", errors.New("preset name required")
+	}
+	return cmd.Args().First(), nil
+}
+
+func runPresetSave(cmd *ufcli.Command) error {
+	opts := cli.GetOptions(cmd)
+	out := output.New(opts.NoColor)
+	out.SetWriter(opts.Stdout)
+
+	name, err := presetName(cmd)
+	if err != nil {
+		return err
+	}
+
+	_, stateSvc := NewServices(opts)
+	monitors, err := stateSvc.Monitors()
+	if err != nil {
+		return fmt.Errorf("load state: %w", err)
+	}
+
+	onlyMonitor := cmd.Root().IsSet(cli.FlagMonitor)
+	if onlyMonitor {
+		if err := resolveFocusedMonitor(opts); err != nil {
+			return err
+		}
+	}
+	preset := config.Preset{}
+	for monitor, mon := range monitors {
+		if onlyMonitor && monitor != opts.Monitor {
+			continue
+		}
+		if mon.Current == nil {
+			continue
+		}
+		layout := mon.Layout()
+		preset[monitor] = config.PresetLayout{
+			Current: layout.Current,
+			Shift:   layout.Shift,
+			Size:    layout.Size,
+			Align:   layout.Align,
+			Margin:  layout.Margin,
+			Height:  layout.Height,
+		}
+	}
+	if len(preset) == 0 {
+		return errors.New("no current percentage set; use 'workspace use' first")
+	}
+
+	if opts.DryRun {
+		out.DryRun()
+		out.Printf("Would save preset %s\n", name)
+		return nil
+	}
+
+	if err := stateSvc.SavePreset(name, preset); err != nil {
+		return fmt.Errorf("write state: %w", err)
+	}
+
+	out.Success("Saved preset %s (%d monitor(s))\n", name, len(preset))
+	return nil
+}
+
+func runPresetApply(cmd *ufcli.Command) error {
+	opts := cli.GetOptions(cmd)
+	out := NewPrinter(opts)
+
+	name, err := presetName(cmd)
+	if err != nil {
+		return err
+	}
+
+	_, stateSvc := NewServices(opts)
+	preset, err := stateSvc.Preset(name)
+	if err != nil {
+		return err
+	}
+
+	var reqs []layoutRequest
+	for _, monitor := range slices.Sorted(maps.Keys(preset)) {
+		reqs = append(reqs, layoutRequestFor(monitor, preset[monitor].Layout()))
+	}
+
+	return applyLayouts(opts, out, reqs)
+}
+
+func runPresetList(cmd *ufcli.Command) error {
+	opts := cli.GetOptions(cmd)
+	out := output.New(opts.NoColor)
+	out.SetWriter(opts.Stdout)
+
+	_, stateSvc := NewServices(opts)
+	presets, err := stateSvc.Presets()
+	if err != nil {
+		return fmt.Errorf("load state: %w", err)
+	}
+
+	out.PrintHeader("Presets")
+	if len(presets) == 0 {
+		out.Unset("  (no presets saved)\n")
+		return nil
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(presets)) {
+		out.Label("  %s:\n", name)
+		preset := presets[name]
+		for _, monitor := range slices.Sorted(maps.Keys(preset)) {
+			layout := preset[monitor]
+			out.Printf("    ")
+			out.PrintKeyValue(monitor, formatLayout(layout.Layout()))
+		}
+	}
+
+	return nil
+}
+
+func runPresetDelete(cmd *ufcli.Command) error {
+	opts := cli.GetOptions(cmd)
+	out := output.New(opts.NoColor)
+	out.SetWriter(opts.Stdout)
+
+	name, err := presetName(cmd)
+	if err != nil {
+		return err
+	}
+
+	_, stateSvc := NewServices(opts)
+	if _, err := stateSvc.Preset(name); err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		out.DryRun()
+		out.Printf("Would delete preset %s\n", name)
+		return nil
+	}
+
+	if err := stateSvc.DeletePreset(name); err != nil {
+		return fmt.Errorf("write state: %w", err)
+	}
+
+	out.Success("Deleted preset %s\n", name)
+	return nil
+}
@@ -0,0 +1,75 @@
+package workspace
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mholtzscher/aerospace-utils/internal/cli"
+	"github.com/mholtzscher/aerospace-utils/internal/display"
+	"github.com/mholtzscher/aerospace-utils/internal/output"
+)
+
+// Reapply recalculates the gaps of every connected monitor with a saved
+// layout for the given displays, logging to out. Failures are logged rather
+// than returned, so a daemon keeps running.
+func Reapply(opts *cli.GlobalOptions, out *output.Printer, displays []display.Info) {
+	err := WithLock(opts, func() error {
+		reqs, err := reapplyRequests(opts, out, displays)
+		if err != nil {
+			return err
+		}
+		if len(reqs) == 0 {
+			out.Printf("No saved layouts for the connected displays\n")
+			return nil
+		}
+		return applyLayouts(opts, out, reqs)
+	})
+	if err != nil {
+		out.Error("Reapply failed: %v\n", err)
+	}
+}
+
+// reapplyRequests returns a request for each monitor in the state that has a
+// layout, is connected and has gaps in the config (or --create is set). The
+// layouts, including any height, are sized for displays rather than detecting them again, and are
+// replaced rather than recorded, so display changes do not fill the undo
+// history.
+func reapplyRequests(opts *cli.GlobalOptions, out *output.Printer, displays []display.Info) ([]layoutRequest, error) {
+	configSvc, stateSvc := NewServices(opts)
+	monitors, err := stateSvc.Monitors()
+	if err != nil {
+		return nil, fmt.Errorf("load state: %w", err)
+	}
+	entries, err := configSvc.MonitorNames()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+
+	names := make([]string, 0, len(monitors))
+	for name := range monitors {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var reqs []layoutRequest
+	for _, name := range names {
+		mon := monitors[name]
+		if mon.Current == nil && mon.Default == nil {
+			continue
+		}
+		if _, ok := matchDisplay(name, displays); !ok {
+			out.Printf("Skipping %s: not connected\n", name)
+			continue
+		}
+		// An overlay config is rendered with entries for every monitor.
+		creates := opts.Create || opts.BaseConfig != ""
+		if !creates && !slices.ContainsFunc(entries, func(e string) bool { return strings.EqualFold(e, name) }) {
+			out.Printf("Skipping %s: no gaps in config (use --%s to add them)\n", name, cli.FlagCreate)
+			continue
+		}
+		// A stored height is recalculated too, for the display's new height.
+		reqs = append(reqs, layoutRequest{monitor: name, history: historyNone, height: mon.Height, displays: displays})
+	}
+	return reqs, nil
+}
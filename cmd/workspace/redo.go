@@ -0,0 +1,51 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mholtzscher/aerospace-utils/internal/cli"
+	"github.com/mholtzscher/aerospace-utils/internal/config"
+	ufcli "github.com/urfave/cli/v3"
+)
+
+func newRedoCommand() *ufcli.Command {
+	return &ufcli.Command{
+		Name:  "redo",
+		Usage: "Re-apply the layout reverted by undo",
+		Description: `Re-apply the workspace size and shift most recently reverted by undo.
+
+Any new change made after an undo clears the redo list.
+
+Examples:
+  aerospace-utils workspace redo
+  aerospace-utils workspace redo --monitor "Dell U2722D"`,
+		Action: func(ctx context.Context, cmd *ufcli.Command) error {
+			return WithLock(cli.GetOptions(cmd), func() error { return runRedo(cmd) })
+		},
+	}
+}
+
+func runRedo(cmd *ufcli.Command) error {
+	opts := cli.GetOptions(cmd)
+	out := NewPrinter(opts)
+
+	if err := resolveFocusedMonitor(opts); err != nil {
+		return err
+	}
+
+	_, stateSvc := NewServices(opts)
+	monState, err := stateSvc.GetMonitorState(opts.Monitor)
+	if err != nil {
+		return fmt.Errorf("load state: %w", err)
+	}
+	if len(monState.Redo) == 0 {
+		return fmt.Errorf("%w for %s", config.ErrNothingToRedo, opts.Monitor)
+	}
+
+	next := monState.Redo[len(monState.Redo)-1]
+	req := layoutRequestFor(opts.Monitor, next.Layout())
+	req.history = historyRedo
+	req.resetHeight = next.Height == nil && monState.Height != nil
+	return applyLayouts(opts, out, []layoutRequest{req})
+}
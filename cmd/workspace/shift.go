@@ -5,11 +5,9 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/mholtzscher/aerospace-utils/internal/aerospace"
 	"github.com/mholtzscher/aerospace-utils/internal/cli"
 	"github.com/mholtzscher/aerospace-utils/internal/config"
 	"github.com/mholtzscher/aerospace-utils/internal/gaps"
-	"github.com/mholtzscher/aerospace-utils/internal/output"
 	ufcli "github.com/urfave/cli/v3"
 )
 
@@ -27,12 +25,14 @@ Shift right by 5% -> left gap 30%, right gap 20%
 
 Shifts are cumulative - each command adds to the current shift.
 Running shift without --by resets shift to 0 (centered).
+A workspace pinned with align is released and shifted from the center.
 
 Examples:
   aerospace-utils workspace shift           # reset to centered
   aerospace-utils workspace shift -b -5     # shift 5% left from current
   aerospace-utils workspace shift -b 5      # shift 5% right from current
-  aerospace-utils workspace shift -b 3      # another 3% right (now 8% right total)`,
+  aerospace-utils workspace shift -b 3      # another 3% right (now 8% right total)
+  aerospace-utils workspace shift --all     # re-center every monitor`,
 		Flags: []ufcli.Flag{
 			&ufcli.IntFlag{
 				Name:    flagShiftBy,
@@ -42,127 +42,127 @@ Examples:
 			},
 		},
 		Action: func(ctx context.Context, cmd *ufcli.Command) error {
-			return runShift(cmd)
+			return WithLock(cli.GetOptions(cmd), func() error { return runShift(cmd) })
 		},
 	}
 }
 
 func runShift(cmd *ufcli.Command) error {
 	opts := cli.GetOptions(cmd)
-	out := output.New(opts.NoColor)
+	out := NewPrinter(opts)
 
-	amount := cmd.Int(flagShiftBy)
-
-	// Create services
-	configSvc := config.NewAerospaceService(opts.ConfigPath)
-	stateSvc := config.NewWorkspaceService(opts.StatePath)
-
-	// Get current state for this monitor
-	monState, err := stateSvc.GetMonitorState(opts.Monitor)
+	monitors, err := targetMonitors(opts)
 	if err != nil {
-		return fmt.Errorf("load state: %w", err)
-	}
-	if monState.Current == nil {
-		return errors.New("no current percentage set; use 'workspace use' first")
-	}
-
-	percentage := *monState.Current
-	if err := gaps.ValidatePercentage(percentage); err != nil {
 		return err
 	}
 
-	// Get current shift (0 if not set)
-	currentShift := int64(0)
-	if monState.Shift != nil {
-		currentShift = *monState.Shift
+	// Create services
+	configSvc, stateSvc := NewServices(opts)
+
+	var plans []layoutPlan
+	for _, monitor := range monitors {
+		plan, err := planShift(cmd, opts, stateSvc, monitor)
+		if opts.All && errors.Is(err, errNoCurrent) {
+			out.Warning("Skipping %s: no current percentage set\n", monitor)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		plans = append(plans, plan)
+	}
+	if len(plans) == 0 {
+		return errNoCurrent
 	}
 
-	// Calculate new shift
-	var newShift int64
-	if cmd.IsSet(flagShiftBy) {
-		// Flag was explicitly set - add to current shift (cumulative)
-		newShift = currentShift + int64(amount)
-	} else {
-		// No flag provided - reset to 0 (centered)
-		newShift = 0
+	if opts.DryRun {
+		if opts.Output.Structured() {
+			return encodeLayouts(opts, plans, ReloadResult{Status: ReloadSkipped, Message: "dry run"})
+		}
+		for _, plan := range plans {
+			out.DryRun()
+			out.Printf("Would set %s to %s (left: %dpx (%d%%), right: %dpx (%d%%))\n",
+				plan.req.monitor, formatWidth(plan.percentage, plan.size),
+				plan.gaps.LeftGapPixels, plan.gaps.LeftGapPercent,
+				plan.gaps.RightGapPixels, plan.gaps.RightGapPercent)
+		}
+		return nil
 	}
 
-	// Get monitor width
-	monitorWidth, err := resolveMonitorWidth(opts)
+	reload, err := commitLayouts(opts, configSvc, stateSvc, plans)
 	if err != nil {
 		return err
 	}
-
-	// Validate shift is within bounds
-	if err := gaps.ValidateShift(monitorWidth, percentage, newShift); err != nil {
-		return fmt.Errorf("invalid shift: %w", err)
+	if opts.Output.Structured() {
+		return encodeLayouts(opts, plans, reload)
 	}
 
-	shift := newShift
-
-	// Calculate shifted gaps
-	shiftedGaps := gaps.CalculateShiftedGaps(monitorWidth, percentage, int64(shift))
+	for _, plan := range plans {
+		// Build success message
+		shiftMsg := ""
+		if plan.shift == 0 {
+			shiftMsg = " (centered)"
+		} else if plan.shift > 0 {
+			shiftMsg = fmt.Sprintf(" (shifted %d%% right)", plan.shift)
+		} else {
+			shiftMsg = fmt.Sprintf(" (shifted %d%% left)", -plan.shift)
+		}
 
-	if opts.DryRun {
-		out.DryRun()
-		out.Printf("Would set %s to %d%% (left: %dpx (%d%%), right: %dpx (%d%%))\n",
-			opts.Monitor, percentage,
-			shiftedGaps.LeftGapPixels, shiftedGaps.LeftGapPercent,
-			shiftedGaps.RightGapPixels, shiftedGaps.RightGapPercent)
-		return nil
+		out.Success("Set %s to %s (left: %dpx (%d%%), right: %dpx (%d%%))%s%s\n",
+			plan.req.monitor, formatWidth(plan.percentage, plan.size),
+			plan.gaps.LeftGapPixels, plan.gaps.LeftGapPercent,
+			plan.gaps.RightGapPixels, plan.gaps.RightGapPercent,
+			shiftMsg, reload.Suffix())
 	}
 
-	// Check if config exists
-	exists, err := configSvc.Exists()
+	return nil
+}
+
+// planShift calculates the new shift and gaps for a monitor. Unlike use, an
+// out-of-range shift is an error rather than being reset.
+func planShift(cmd *ufcli.Command, opts *cli.GlobalOptions, stateSvc *config.WorkspaceService, monitor string) (layoutPlan, error) {
+	// Get current state for this monitor
+	monState, err := stateSvc.GetMonitorState(monitor)
 	if err != nil {
-		return fmt.Errorf("check config: %w", err)
+		return layoutPlan{}, fmt.Errorf("load state: %w", err)
 	}
-	if !exists {
-		return fmt.Errorf("config file not found: %s\nCreate it manually or run 'aerospace' to generate a default config", configSvc.ConfigPath())
+	if monState.Current == nil {
+		return layoutPlan{}, errNoCurrent
 	}
 
-	// Update config with asymmetric gaps
-	if err := configSvc.SetMonitorAsymmetricGaps(opts.Monitor, shiftedGaps.LeftGapPixels, shiftedGaps.RightGapPixels); err != nil {
-		return fmt.Errorf("update config: %w", err)
+	percentage := *monState.Current
+	if err := gaps.ValidatePercentage(percentage); err != nil {
+		return layoutPlan{}, err
 	}
 
-	if err := configSvc.Write(); err != nil {
-		return fmt.Errorf("write config: %w", err)
+	// Calculate new shift
+	var newShift int64
+	if cmd.IsSet(flagShiftBy) {
+		// Flag was explicitly set - add to current shift (cumulative)
+		newShift = valueOrZero(monState.Shift) + int64(cmd.Int(flagShiftBy))
+	} else {
+		// No flag provided - reset to 0 (centered)
+		newShift = 0
 	}
 
-	// Update state with shift
-	if err := stateSvc.SetShift(opts.Monitor, int64(shift)); err != nil {
-		return fmt.Errorf("write state: %w", err)
+	// Get monitor width
+	monitorWidth, err := resolveMonitorWidth(opts, monitor, nil)
+	if err != nil {
+		return layoutPlan{}, err
 	}
 
-	// Reload aerospace config
-	reloadStatus := ""
-	if !opts.NoReload {
-		bin, err := aerospace.FindBinary()
-		if err != nil {
-			reloadStatus = " (aerospace not found)"
-		} else if err := bin.ReloadConfig(); err != nil {
-			reloadStatus = fmt.Sprintf(" (reload failed: %v)", err)
-		}
-	} else {
-		reloadStatus = " (reload skipped)"
+	// Keep an absolute size so the workspace width stays the same
+	size := gaps.Size{Value: float64(percentage), Unit: gaps.UnitPercent}
+	if stored := storedSize(monState.Size); stored != nil {
+		size = *stored
 	}
 
-	// Build success message
-	shiftMsg := ""
-	if shift == 0 {
-		shiftMsg = " (centered)"
-	} else if shift > 0 {
-		shiftMsg = fmt.Sprintf(" (shifted %d%% right)", shift)
-	} else {
-		shiftMsg = fmt.Sprintf(" (shifted %d%% left)", -shift)
+	center := gaps.AlignCenter
+	req := layoutRequest{monitor: monitor, shift: &newShift, align: &center}
+	plan, err := planSize(opts, req, size, monitorWidth, newShift)
+	if errors.Is(err, gaps.ErrInvalidShift) {
+		return layoutPlan{}, fmt.Errorf("invalid shift: %w", err)
 	}
-
-	out.Success("Set %s to %d%% (left: %dpx (%d%%), right: %dpx (%d%%))%s%s\n",
-		opts.Monitor, percentage,
-		shiftedGaps.LeftGapPixels, shiftedGaps.LeftGapPercent,
-		shiftedGaps.RightGapPixels, shiftedGaps.RightGapPercent,
-		shiftMsg, reloadStatus)
-
-	return nil
+	plan.align = center
+	return plan, err
 }
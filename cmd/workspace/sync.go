@@ -0,0 +1,227 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mholtzscher/aerospace-utils/internal/cli"
+	"github.com/mholtzscher/aerospace-utils/internal/config"
+	"github.com/mholtzscher/aerospace-utils/internal/gaps"
+	"github.com/mholtzscher/aerospace-utils/internal/output"
+	ufcli "github.com/urfave/cli/v3"
+)
+
+func newSyncCommand() *ufcli.Command {
+	return &ufcli.Command{
+		Name:  "sync",
+		Usage: "Save the layout already in aerospace.toml to the state file",
+		Description: `Read a monitor's left and right gaps from aerospace.toml and, with the
+monitor's width, work out the percentage and shift that produce them. The
+result is saved as the monitor's current layout, so adjust and shift work on
+configs whose gaps were written by hand.
+
+Percentages keep two decimals and shifts are whole percentages, so some gaps
+cannot be reproduced exactly. Those monitors are reported with the gaps the
+saved layout gives instead. Monitors whose saved layout already produces the
+configured gaps are left alone. aerospace.toml itself is never changed.
+
+Examples:
+  aerospace-utils workspace sync
+  aerospace-utils workspace sync --all
+  aerospace-utils workspace sync --all --dry-run`,
+		Action: func(ctx context.Context, cmd *ufcli.Command) error {
+			return WithLock(cli.GetOptions(cmd), func() error { return runSync(cmd) })
+		},
+	}
+}
+
+// errNothingToSync indicates every targeted monitor was skipped.
+var errNothingToSync = errors.New("no monitors could be synced")
+
+// syncStatus is the outcome of syncing one monitor.
+type syncStatus string
+
+const (
+	syncSynced    syncStatus = "synced"
+	syncUnchanged syncStatus = "unchanged"
+	syncSkipped   syncStatus = "skipped"
+)
+
+// syncResult is the structured result for one monitor.
+type syncResult struct {
+	Monitor        string     `json:"monitor"`
+	Status         syncStatus `json:"status"`
+	Width          int64      `json:"width,omitempty"`
+	LeftGapPixels  int64      `json:"left_gap_px"`
+	RightGapPixels int64      `json:"right_gap_px"`
+	Percentage     float64    `json:"percentage,omitempty"`
+	Size           string     `json:"size,omitempty"`
+	Shift          int64      `json:"shift"`
+	Align          string     `json:"align,omitempty"`
+	Margin         int64      `json:"margin,omitempty"`
+	Exact          bool       `json:"exact"`
+	// Gaps the saved layout produces, when not exact.
+	SavedLeftGapPixels  int64  `json:"saved_left_gap_px,omitempty"`
+	SavedRightGapPixels int64  `json:"saved_right_gap_px,omitempty"`
+	Message             string `json:"message,omitempty"`
+}
+
+func runSync(cmd *ufcli.Command) error {
+	opts := cli.GetOptions(cmd)
+	out := NewPrinter(opts)
+
+	monitors, err := targetMonitors(opts)
+	if err != nil {
+		return err
+	}
+
+	configSvc, stateSvc := NewServices(opts)
+	summary, err := configSvc.Summary()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	left, right := gapsByMonitor(summary.LeftGaps), gapsByMonitor(summary.RightGaps)
+
+	results := make([]syncResult, 0, len(monitors))
+	changed, skipped := false, 0
+	for _, monitor := range monitors {
+		result := syncMonitor(opts, stateSvc, monitor, left, right)
+		switch result.Status {
+		case syncSynced:
+			changed = true
+		case syncSkipped:
+			skipped++
+		}
+		results = append(results, result)
+	}
+
+	if changed && !opts.DryRun {
+		if err := stateSvc.Write(); err != nil {
+			return fmt.Errorf("write state: %w", err)
+		}
+	}
+
+	if opts.Output.Structured() {
+		err = output.EncodeTo(opts.Stdout, opts.Output, struct {
+			DryRun   bool         `json:"dry_run"`
+			Wrote    bool         `json:"wrote"`
+			Monitors []syncResult `json:"monitors"`
+		}{opts.DryRun, changed && !opts.DryRun, results})
+		if err != nil {
+			return err
+		}
+	} else {
+		printSyncResults(opts, out, results)
+	}
+
+	if skipped == len(results) {
+		return errNothingToSync
+	}
+	return nil
+}
+
+// syncMonitor infers the layout of one monitor from its configured gaps and
+// records it in the state in memory.
+func syncMonitor(opts *cli.GlobalOptions, stateSvc *config.WorkspaceService, monitor string, left, right map[string]int64) syncResult {
+	result := syncResult{Monitor: monitor, Status: syncSkipped}
+
+	leftGap, hasLeft := left[monitor]
+	rightGap, hasRight := right[monitor]
+	if !hasLeft || !hasRight {
+		result.Message = "needs both a left and a right gap in aerospace.toml"
+		return result
+	}
+	result.LeftGapPixels, result.RightGapPixels = leftGap, rightGap
+
+	width, err := resolveMonitorWidth(opts, monitor, nil)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	result.Width = width
+
+	// A saved layout that still produces these gaps is kept as it is,
+	// including any absolute size or alignment. Without a saved current
+	// percentage the plan would come from a default, so there is nothing to
+	// keep.
+	mon, err := stateSvc.GetMonitorState(monitor)
+	if err != nil {
+		result.Message = fmt.Sprintf("load state: %v", err)
+		return result
+	}
+	if plan, err := planLayout(opts, stateSvc, layoutRequest{monitor: monitor}); mon.Current != nil && err == nil &&
+		plan.gaps.LeftGapPixels == leftGap && plan.gaps.RightGapPixels == rightGap {
+		layout := plan.layout()
+		result.Status = syncUnchanged
+		result.Percentage, result.Size, result.Shift = layout.Current, layout.Size, layout.Shift
+		result.Align, result.Margin = layout.Align, layout.Margin
+		result.Exact = true
+		return result
+	}
+
+	inferred, err := gaps.InferLayout(width, leftGap, rightGap, opts.Rounding)
+	if err != nil {
+		result.Message = fmt.Sprintf("%dpx and %dpx gaps on a %dpx monitor: %v", leftGap, rightGap, width, err)
+		return result
+	}
+
+	layout := config.Layout{Current: inferred.Percentage, Shift: inferred.Shift, Height: valueOrZero(mon.Height)}
+	if inferred.Align != gaps.AlignCenter {
+		layout.Align, layout.Margin = string(inferred.Align), inferred.Margin
+	}
+	if err := stateSvc.SetLayout(monitor, layout, false); err != nil {
+		result.Message = fmt.Sprintf("update state: %v", err)
+		return result
+	}
+
+	result.Status = syncSynced
+	result.Percentage, result.Shift = layout.Current, layout.Shift
+	result.Align, result.Margin = layout.Align, layout.Margin
+	result.Exact = inferred.Exact
+	if !inferred.Exact {
+		result.SavedLeftGapPixels = inferred.Gaps.LeftGapPixels
+		result.SavedRightGapPixels = inferred.Gaps.RightGapPixels
+	}
+	return result
+}
+
+func printSyncResults(opts *cli.GlobalOptions, out *output.Printer, results []syncResult) {
+	verb := "Synced"
+	if opts.DryRun {
+		verb = "Would sync"
+	}
+
+	for _, r := range results {
+		layout := formatLayout(config.Layout{Current: r.Percentage, Size: r.Size, Shift: r.Shift, Align: r.Align, Margin: r.Margin})
+		switch {
+		case r.Status == syncSkipped:
+			out.Warning("Skipping %s: %s\n", r.Monitor, r.Message)
+		case r.Status == syncUnchanged:
+			out.Printf("%s already matches aerospace.toml (%s)\n", r.Monitor, layout)
+		case r.Exact:
+			if opts.DryRun {
+				out.DryRun()
+			}
+			out.Success("%s %s: %s (left: %dpx, right: %dpx)\n", verb, r.Monitor, layout, r.LeftGapPixels, r.RightGapPixels)
+		default:
+			if opts.DryRun {
+				out.DryRun()
+			}
+			out.Warning("%s %s: %s, which gives left: %dpx, right: %dpx instead of %dpx, %dpx (not exactly representable)\n",
+				verb, r.Monitor, layout, r.SavedLeftGapPixels, r.SavedRightGapPixels, r.LeftGapPixels, r.RightGapPixels)
+		}
+	}
+}
+
+// gapsByMonitor indexes per-monitor gaps by monitor name. Like Aerospace,
+// it uses the first entry for a monitor that is listed more than once.
+func gapsByMonitor(entries []config.MonitorGap) map[string]int64 {
+	byMonitor := make(map[string]int64, len(entries))
+	for _, g := range entries {
+		if _, ok := byMonitor[g.Name]; !ok {
+			byMonitor[g.Name] = g.Value
+		}
+	}
+	return byMonitor
+}
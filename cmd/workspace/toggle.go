@@ -0,0 +1,94 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mholtzscher/aerospace-utils/internal/cli"
+	"github.com/mholtzscher/aerospace-utils/internal/gaps"
+	ufcli "github.com/urfave/cli/v3"
+)
+
+func newToggleCommand() *ufcli.Command {
+	return &ufcli.Command{
+		Name:      "toggle",
+		Usage:     "Switch between two workspace sizes",
+		ArgsUsage: "<a> <b>",
+		Description: `Switch the workspace size between two percentages.
+
+If the current percentage is <a>, the workspace is set to <b>; otherwise it
+is set to <a>. The shift is kept, as with use.
+
+With --all, every monitor is set to the size picked for --monitor so they
+switch together.
+
+Examples:
+  aerospace-utils workspace toggle 100 60
+  aerospace-utils workspace toggle 100 60 --monitor focused`,
+		Action: func(ctx context.Context, cmd *ufcli.Command) error {
+			return WithLock(cli.GetOptions(cmd), func() error { return runToggle(cmd) })
+		},
+	}
+}
+
+func runToggle(cmd *ufcli.Command) error {
+	opts := cli.GetOptions(cmd)
+	out := NewPrinter(opts)
+
+	if cmd.Args().Len() != 2 {
+		return errors.New("toggle needs exactly two percentages")
+	}
+	values, err := parsePercentages(cmd.Args().Slice())
+	if err != nil {
+		return err
+	}
+
+	current, err := currentPercentage(opts)
+	if err != nil {
+		return err
+	}
+
+	next := values[0]
+	if current != nil && *current == values[0] {
+		next = values[1]
+	}
+	return applyPercentage(cmd, opts, out, &next)
+}
+
+// parsePercentages parses percentage arguments such as "60" or "62.5%".
+func parsePercentages(args []string) ([]float64, error) {
+	values := make([]float64, 0, len(args))
+	for _, arg := range args {
+		size, err := gaps.ParseSize(arg)
+		if err != nil {
+			return nil, err
+		}
+		if size.Unit != gaps.UnitPercent {
+			return nil, fmt.Errorf("invalid percentage %q: sizes in %s are not supported here", arg, size.Unit)
+		}
+		if err := gaps.ValidatePercentage(size.Value); err != nil {
+			return nil, fmt.Errorf("invalid percentage %q: %w", arg, err)
+		}
+		values = append(values, size.Value)
+	}
+	return values, nil
+}
+
+// currentPercentage returns the stored current percentage of the --monitor
+// monitor, or nil if none is set.
+func currentPercentage(opts *cli.GlobalOptions) (*float64, error) {
+	if err := resolveFocusedMonitor(opts); err != nil {
+		return nil, err
+	}
+
+	_, stateSvc := NewServices(opts)
+	monitors, err := stateSvc.Monitors()
+	if err != nil {
+		return nil, fmt.Errorf("load state: %w", err)
+	}
+	if mon := monitors[opts.Monitor]; mon != nil {
+		return mon.Current, nil
+	}
+	return nil, nil
+}
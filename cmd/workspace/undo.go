@@ -0,0 +1,53 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mholtzscher/aerospace-utils/internal/cli"
+	"github.com/mholtzscher/aerospace-utils/internal/config"
+	ufcli "github.com/urfave/cli/v3"
+)
+
+func newUndoCommand() *ufcli.Command {
+	return &ufcli.Command{
+		Name:  "undo",
+		Usage: "Restore the previous workspace size and shift",
+		Description: `Restore the layout that was active before the last change to this monitor.
+
+Each use, adjust or shift that changes the layout records the previous
+percentage and shift in the state file. Undo re-applies the most recent
+entry; redo reverses an undo.
+
+Examples:
+  aerospace-utils workspace undo
+  aerospace-utils workspace undo --monitor "Dell U2722D"`,
+		Action: func(ctx context.Context, cmd *ufcli.Command) error {
+			return WithLock(cli.GetOptions(cmd), func() error { return runUndo(cmd) })
+		},
+	}
+}
+
+func runUndo(cmd *ufcli.Command) error {
+	opts := cli.GetOptions(cmd)
+	out := NewPrinter(opts)
+
+	if err := resolveFocusedMonitor(opts); err != nil {
+		return err
+	}
+
+	_, stateSvc := NewServices(opts)
+	monState, err := stateSvc.GetMonitorState(opts.Monitor)
+	if err != nil {
+		return fmt.Errorf("load state: %w", err)
+	}
+	if len(monState.History) == 0 {
+		return fmt.Errorf("%w for %s", config.ErrNothingToUndo, opts.Monitor)
+	}
+
+	prev := monState.History[len(monState.History)-1]
+	req := layoutRequestFor(opts.Monitor, prev.Layout())
+	req.history = historyUndo
+	req.resetHeight = prev.Height == nil && monState.Height != nil
+	return applyLayouts(opts, out, []layoutRequest{req})
+}
@@ -4,10 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 
-	"github.com/mholtzscher/aerospace-utils/internal/aerospace"
 	"github.com/mholtzscher/aerospace-utils/internal/cli"
 	"github.com/mholtzscher/aerospace-utils/internal/config"
 	"github.com/mholtzscher/aerospace-utils/internal/display"
@@ -16,7 +14,10 @@ import (
 	ufcli "github.com/urfave/cli/v3"
 )
 
-const flagSetDefault = "set-default"
+const (
+	flagSetDefault = "set-default"
+	flagHeight     = "height"
+)
 
 func newUseCommand() *ufcli.Command {
 	return &ufcli.Command{
@@ -24,206 +25,219 @@ func newUseCommand() *ufcli.Command {
 		Usage: "Set workspace size percentage",
 		Description: `Set the workspace size as a percentage of the monitor width.
 
-The gap size is calculated to achieve the desired percentage.
+The gap size is calculated to achieve the desired percentage, which may
+have up to two decimal places (62.5).
 If no percentage is given, uses the current or default percentage. If the
 state file is missing or empty, defaults to 60%.
 
+The size can also be an absolute width in pixels (2560px) or centimeters
+(60cm). Centimeters need the monitor's physical width from display detection.
+The unit is stored, so re-applying on another monitor keeps the same width.
+
+With --height, the workspace height is set as a percentage of the monitor
+height too, using per-monitor gaps.outer.top/bottom entries so the workspace
+is centered vertically. A single top or bottom value for every monitor is
+turned into a per-monitor array that keeps it as the fallback.
+
+With --align left or right, the workspace is pinned to that edge of the
+monitor, --margin pixels away from it. The alignment is remembered until
+changed with align or shift.
+
+With --all, every monitor in the config is updated with one write and one
+reload; each monitor's gaps are calculated from its own width.
+
 Examples:
   aerospace-utils workspace use 40
+  aerospace-utils workspace use 62.5
   aerospace-utils workspace use 80 --monitor "Dell U2722D"
+  aerospace-utils workspace use 70 --all
+  aerospace-utils workspace use 2560px
+  aerospace-utils workspace use 60cm
+  aerospace-utils workspace use 80 --height 90
+  aerospace-utils workspace use 60 --align left --margin 20
   aerospace-utils workspace use --set-default 50`,
 		Flags: []ufcli.Flag{
 			&ufcli.BoolFlag{
 				Name:  flagSetDefault,
 				Usage: "Also set as the default percentage for this monitor",
 			},
+			&ufcli.FloatFlag{
+				Name:  flagHeight,
+				Usage: "Workspace height as a percentage of the monitor height",
+			},
+			&ufcli.StringFlag{
+				Name:  flagAlign,
+				Usage: "Pin the workspace to an edge: left, center or right",
+			},
+			&ufcli.IntFlag{
+				Name:  flagMargin,
+				Usage: "Pixels between the workspace and the aligned edge",
+			},
 		},
 		Action: func(ctx context.Context, cmd *ufcli.Command) error {
-			return runUse(cmd)
+			return WithLock(cli.GetOptions(cmd), func() error { return runUse(cmd) })
 		},
 	}
 }
 
 func runUse(cmd *ufcli.Command) error {
 	opts := cli.GetOptions(cmd)
-	out := output.New(opts.NoColor)
+	out := NewPrinter(opts)
 
-	// Parse optional percentage argument
-	var explicitPercent *int64
-	if cmd.Args().Len() > 0 {
-		p, err := strconv.ParseInt(cmd.Args().Get(0), 10, 64)
-		if err != nil {
-			return fmt.Errorf("invalid percentage %q: %w", cmd.Args().Get(0), err)
-		}
-		explicitPercent = &p
+	// Parse optional size argument: a percentage, or a width in px or cm
+	if cmd.Args().Len() == 0 {
+		return applyPercentage(cmd, opts, out, nil)
 	}
-
-	return applyPercentage(cmd, opts, out, explicitPercent)
+	size, err := gaps.ParseSize(cmd.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	if size.Unit == gaps.UnitPercent {
+		p := size.Value
+		return applyPercentage(cmd, opts, out, &p)
+	}
+	return applySize(cmd, opts, out, nil, &size)
 }
 
-func applyPercentage(cmd *ufcli.Command, opts *cli.GlobalOptions, out *output.Printer, explicitPercent *int64) error {
-	configSvc := config.NewAerospaceService(opts.ConfigPath)
-	stateSvc := config.NewWorkspaceService(opts.StatePath)
+// applyPercentage applies a percentage (or the stored one when nil) to the
+// target monitors.
+func applyPercentage(cmd *ufcli.Command, opts *cli.GlobalOptions, out *output.Printer, explicitPercent *float64) error {
+	return applySize(cmd, opts, out, explicitPercent, nil)
+}
 
-	// Resolve percentage
-	percentage, err := stateSvc.ResolvePercentage(opts.Monitor, explicitPercent)
+// applySize applies an absolute size, or else a percentage, to the target
+// monitors.
+func applySize(cmd *ufcli.Command, opts *cli.GlobalOptions, out *output.Printer, explicitPercent *float64, size *gaps.Size) error {
+	monitors, err := targetMonitors(opts)
 	if err != nil {
-		return fmt.Errorf("load state: %w", err)
-	}
-	if percentage == nil {
-		return errors.New("no percentage specified and no current/default set for this monitor")
+		return err
 	}
 
-	// Validate percentage
-	if err := gaps.ValidatePercentage(*percentage); err != nil {
-		return err
+	var height *float64
+	if cmd.IsSet(flagHeight) {
+		h := gaps.RoundPercentage(cmd.Float(flagHeight))
+		height = &h
 	}
 
-	// Get monitor width
-	monitorWidth, err := resolveMonitorWidth(opts)
+	align, margin, err := alignmentFlags(cmd)
 	if err != nil {
 		return err
 	}
 
-	// Check for existing shift and apply asymmetric gaps if set
-	originalShift, err := stateSvc.GetShift(opts.Monitor)
-	if err != nil {
-		return fmt.Errorf("load shift: %w", err)
-	}
-	shift := originalShift
-
-	useAsymmetric := false
-	var shiftedGaps gaps.ShiftedGaps
-	var symmetricGapSize int64
-	var gapMsg string
-
-	if shift != 0 {
-		if err := gaps.ValidateShift(monitorWidth, *percentage, shift); err != nil {
-			// Shift is no longer valid for the current percentage.
-			shift = 0
-		} else {
-			useAsymmetric = true
-			shiftedGaps = gaps.CalculateShiftedGaps(monitorWidth, *percentage, shift)
-			gapMsg = fmt.Sprintf("(left: %dpx (%d%%), right: %dpx (%d%%))",
-				shiftedGaps.LeftGapPixels, shiftedGaps.LeftGapPercent,
-				shiftedGaps.RightGapPixels, shiftedGaps.RightGapPercent)
-		}
+	reqs := make([]layoutRequest, 0, len(monitors))
+	for _, monitor := range monitors {
+		reqs = append(reqs, layoutRequest{
+			monitor:    monitor,
+			percentage: explicitPercent,
+			size:       size,
+			height:     height,
+			align:      align,
+			margin:     margin,
+			setDefault: cmd.Bool(flagSetDefault),
+		})
 	}
+	return applyLayouts(opts, out, reqs)
+}
 
-	if !useAsymmetric {
-		symmetricGapSize = gaps.CalculateGapSize(monitorWidth, *percentage)
-		gapMsg = fmt.Sprintf("(%dpx gaps)", symmetricGapSize)
+// updateConfigError wraps a config update failure, pointing at --create when
+// the monitor has no gap entries yet.
+func updateConfigError(err error) error {
+	if errors.Is(err, config.ErrMonitorNotFound) {
+		return fmt.Errorf("update config: %w (use --create to add it)", err)
 	}
+	return fmt.Errorf("update config: %w", err)
+}
 
-	if opts.DryRun {
-		out.DryRun()
-		out.Printf("Would set %s to %d%% %s\n",
-			opts.Monitor, *percentage, gapMsg)
-		return nil
+// resolveMonitorWidth determines the monitor width to use for gap
+// calculation. displays is a snapshot of the connected displays, or nil to
+// detect them.
+func resolveMonitorWidth(opts *cli.GlobalOptions, monitor string, displays []display.Info) (int64, error) {
+	// Use explicit override if provided
+	if opts.MonitorWidth > 0 {
+		return opts.MonitorWidth, nil
 	}
 
-	// Check if config exists
-	exists, err := configSvc.Exists()
+	d, err := resolveDisplay(monitor, cli.FlagMonitorWidth, displays)
 	if err != nil {
-		return fmt.Errorf("check config: %w", err)
-	}
-	if !exists {
-		return fmt.Errorf("config file not found: %s\nCreate it manually or run 'aerospace' to generate a default config", configSvc.ConfigPath())
+		return 0, err
 	}
+	return d.Width, nil
+}
 
-	if useAsymmetric {
-		if err := configSvc.SetMonitorAsymmetricGaps(opts.Monitor, shiftedGaps.LeftGapPixels, shiftedGaps.RightGapPixels); err != nil {
-			return fmt.Errorf("update config: %w", err)
-		}
-	} else {
-		if err := configSvc.SetMonitorGaps(opts.Monitor, symmetricGapSize); err != nil {
-			return fmt.Errorf("update config: %w", err)
-		}
+// resolveMonitorHeight determines the monitor height to use for vertical gaps.
+func resolveMonitorHeight(opts *cli.GlobalOptions, monitor string, displays []display.Info) (int64, error) {
+	if opts.MonitorHeight > 0 {
+		return opts.MonitorHeight, nil
 	}
 
-	if err := configSvc.Write(); err != nil {
-		return fmt.Errorf("write config: %w", err)
+	d, err := resolveDisplay(monitor, cli.FlagMonitorHeight, displays)
+	if err != nil {
+		return 0, err
 	}
-
-	// Update state - preserves shift by calling Update then SetShift
-	setDefaultFlag := cmd.Bool(flagSetDefault)
-	if err := stateSvc.Update(opts.Monitor, *percentage, setDefaultFlag); err != nil {
-		return fmt.Errorf("write state: %w", err)
+	if d.Height <= 0 {
+		return 0, fmt.Errorf("height of monitor %q is unknown; use --%s", monitor, cli.FlagMonitorHeight)
 	}
+	return d.Height, nil
+}
 
-	// If shift was reset, write 0 to clear it
-	if originalShift != shift {
-		if err := stateSvc.SetShift(opts.Monitor, 0); err != nil {
-			return fmt.Errorf("write state: %w", err)
+// resolveDisplay finds the display for a monitor name among displays, or
+// among the detected displays when displays is nil. overrideFlag names the
+// flag suggested when detection fails.
+func resolveDisplay(monitor, overrideFlag string, displays []display.Info) (display.Info, error) {
+	if displays == nil {
+		// Check if display detection is available
+		if !display.Available() {
+			return display.Info{}, fmt.Errorf("display detection not available; use --%s", overrideFlag)
 		}
-	}
 
-	// Reload aerospace config and build single-line output
-	reloadStatus := ""
-	if !opts.NoReload {
-		bin, err := aerospace.FindBinary()
+		// Enumerate displays and match by name
+		var err error
+		displays, err = display.Enumerate()
 		if err != nil {
-			reloadStatus = " (aerospace not found)"
-		} else if err := bin.ReloadConfig(); err != nil {
-			reloadStatus = fmt.Sprintf(" (reload failed: %v)", err)
+			return display.Info{}, fmt.Errorf("enumerate displays: %w", err)
 		}
-	} else {
-		reloadStatus = " (reload skipped)"
-	}
-
-	defaultSuffix := ""
-	if setDefaultFlag {
-		defaultSuffix = ", set as default"
 	}
-	out.Success("Set %s to %d%% %s%s%s\n",
-		opts.Monitor, *percentage, gapMsg, defaultSuffix, reloadStatus)
 
-	return nil
-}
-
-// resolveMonitorWidth determines the monitor width to use for gap calculation.
-func resolveMonitorWidth(opts *cli.GlobalOptions) (int64, error) {
-	// Use explicit override if provided
-	if opts.MonitorWidth > 0 {
-		return int64(opts.MonitorWidth), nil
+	if d, ok := matchDisplay(monitor, displays); ok {
+		return d, nil
 	}
 
-	// Check if display detection is available
-	if !display.Available() {
-		return 0, errors.New("display detection not available; use --monitor-width")
+	if monitor == "main" {
+		return display.Info{}, errors.New("no primary display found")
 	}
 
-	// For "main" monitor, use the simple main display width
-	if opts.Monitor == "main" {
-		return display.MainWidth()
+	// Build helpful error message
+	var names []string
+	for _, d := range displays {
+		names = append(names, d.Name)
 	}
 
-	// Enumerate displays and match by name
-	displays, err := display.Enumerate()
-	if err != nil {
-		return 0, fmt.Errorf("enumerate displays: %w", err)
-	}
+	return display.Info{}, fmt.Errorf("monitor %q not found; available: %s (use --%s to specify)",
+		monitor, strings.Join(names, ", "), overrideFlag)
+}
 
-	// Try to match by name (case-insensitive)
+// matchDisplay finds the display a monitor name refers to: "main", a display
+// name (case-insensitive), or "secondary" in a two-display setup.
+func matchDisplay(monitor string, displays []display.Info) (display.Info, bool) {
 	for _, d := range displays {
-		if strings.EqualFold(d.Name, opts.Monitor) {
-			return d.Width, nil
+		// Aerospace's "main" is the main display
+		if monitor == "main" && d.Main {
+			return d, true
+		}
+		// Try to match by name (case-insensitive)
+		if strings.EqualFold(d.Name, monitor) {
+			return d, true
 		}
 	}
 
-	// Build helpful error message
-	var names []string
-	for _, d := range displays {
-		names = append(names, d.Name)
+	// Aerospace's "secondary" is the non-main display in a two-display setup
+	if monitor == "secondary" && len(displays) == 2 {
+		for _, d := range displays {
+			if !d.Main {
+				return d, true
+			}
+		}
 	}
 
-	return 0, fmt.Errorf("monitor %q not found; available: %s (use --monitor-width to specify)",
-		opts.Monitor, strings.Join(names, ", "))
-}
-
-// RunWithPercent is called by adjust to apply a calculated percentage.
-func RunWithPercent(cmd *ufcli.Command, percentage int64) error {
-	opts := cli.GetOptions(cmd)
-	out := output.New(opts.NoColor)
-	return applyPercentage(cmd, opts, out, &percentage)
+	return display.Info{}, false
 }
@@ -15,7 +15,16 @@ func NewCommand() *ufcli.Command {
 			newUseCommand(),
 			newAdjustCommand(),
 			newShiftCommand(),
+			newAlignCommand(),
+			newToggleCommand(),
+			newCycleCommand(),
 			newCurrentCommand(),
+			newSyncCommand(),
+			newCheckCommand(),
+			newUndoCommand(),
+			newRedoCommand(),
+			newHistoryCommand(),
+			newPresetCommand(),
 		},
 	}
 }
@@ -1,12 +1,15 @@
-// Package aerospace provides interaction with the aerospace binary.
+// Package aerospace provides interaction with a running aerospace instance,
+// over its IPC socket or through the aerospace binary.
 package aerospace
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 )
 
 // Binary represents the aerospace CLI binary.
@@ -42,22 +45,65 @@ func (b *Binary) Path() string {
 	return b.path
 }
 
+// Run runs the binary with args. A non-zero exit status is reported in the
+// response rather than as an error.
+func (b *Binary) Run(args ...string) (Response, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(b.path, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	resp := Response{Stdout: stdout.String(), Stderr: stderr.String()}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		resp.ExitCode = exitErr.ExitCode()
+		return resp, nil
+	}
+	if err != nil {
+		return resp, fmt.Errorf("run aerospace: %w", err)
+	}
+	return resp, nil
+}
+
 // ReloadConfig runs `aerospace reload-config`.
 func (b *Binary) ReloadConfig() error {
-	cmd := exec.Command(b.path, "reload-config")
+	return reloadConfig(b)
+}
+
+// runner runs aerospace commands.
+type runner interface {
+	Run(args ...string) (Response, error)
+}
 
-	// Capture output for error reporting
-	output, err := cmd.CombinedOutput()
+// reloadConfig runs reload-config through r, turning a failure into an error.
+func reloadConfig(r runner) error {
+	resp, err := r.Run("reload-config")
 	if err != nil {
-		if len(output) > 0 {
-			return fmt.Errorf("aerospace reload-config failed: %s", string(output))
-		}
 		return fmt.Errorf("aerospace reload-config failed: %w", err)
 	}
-
+	if resp.ExitCode != 0 {
+		if output := resp.Output(); output != "" {
+			return fmt.Errorf("aerospace reload-config failed: %s", output)
+		}
+		return fmt.Errorf("aerospace reload-config failed: exit status %d", resp.ExitCode)
+	}
 	return nil
 }
 
+// Response is the result of an aerospace command.
+type Response struct {
+	ExitCode int    `json:"exitCode"`
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+}
+
+// Output returns the command's combined output, for error reporting.
+func (r Response) Output() string {
+	return strings.TrimSpace(r.Stdout + r.Stderr)
+}
+
 // DefaultConfigPath returns the default aerospace config path.
 func DefaultConfigPath() string {
 	home, err := os.UserHomeDir()
@@ -0,0 +1,152 @@
+package aerospace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"time"
+)
+
+// EnvSocket overrides the path of the aerospace IPC socket.
+const EnvSocket = "AEROSPACE_UTILS_AEROSPACE_SOCKET"
+
+// ErrSocketUnavailable indicates no aerospace server is listening on the socket.
+var ErrSocketUnavailable = errors.New("aerospace socket unavailable")
+
+const (
+	socketDialTimeout = time.Second
+	socketTimeout     = 10 * time.Second
+)
+
+// DefaultSocketPath returns the socket aerospace listens on, honoring
+// EnvSocket.
+func DefaultSocketPath() string {
+	if path := os.Getenv(EnvSocket); path != "" {
+		return path
+	}
+
+	name := os.Getenv("USER")
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	return fmt.Sprintf("/tmp/bobko.aerospace-%s.sock", name)
+}
+
+// Socket speaks aerospace's Unix-socket JSON protocol: one request object
+// with the command-line arguments, answered by one response object.
+type Socket struct {
+	path string
+}
+
+// NewSocket returns a client for the socket at path.
+func NewSocket(path string) *Socket {
+	return &Socket{path: path}
+}
+
+// Path returns the socket path.
+func (s *Socket) Path() string {
+	return s.path
+}
+
+// socketRequest is the request sent to aerospace. Older servers read the
+// command name separately; newer ones only use args.
+type socketRequest struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+	Stdin   string   `json:"stdin"`
+}
+
+// Run sends args to aerospace. It returns ErrSocketUnavailable when nothing
+// is listening, so callers can fall back to the binary.
+func (s *Socket) Run(args ...string) (Response, error) {
+	if len(args) == 0 {
+		return Response{}, errors.New("no aerospace command given")
+	}
+
+	conn, err := net.DialTimeout("unix", s.path, socketDialTimeout)
+	if err != nil {
+		return Response{}, fmt.Errorf("%w: %w", ErrSocketUnavailable, err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if err := conn.SetDeadline(time.Now().Add(socketTimeout)); err != nil {
+		return Response{}, fmt.Errorf("aerospace socket: %w", err)
+	}
+
+	req := socketRequest{Command: args[0], Args: args}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return Response{}, fmt.Errorf("send to aerospace socket: %w", err)
+	}
+
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return Response{}, fmt.Errorf("read from aerospace socket: %w", err)
+	}
+	return resp, nil
+}
+
+// Client runs aerospace commands over the IPC socket, falling back to the
+// aerospace binary when no server is listening.
+type Client struct {
+	socket     *Socket
+	findBinary func() (*Binary, error)
+}
+
+// NewClient returns a client for the default socket.
+func NewClient() *Client {
+	return NewClientWithSocket(DefaultSocketPath())
+}
+
+// NewClientWithSocket returns a client for the socket at path.
+func NewClientWithSocket(path string) *Client {
+	return &Client{socket: NewSocket(path), findBinary: FindBinary}
+}
+
+// Run runs an aerospace command. It returns ErrNotFound when neither the
+// socket nor the binary is available.
+func (c *Client) Run(args ...string) (Response, error) {
+	resp, err := c.socket.Run(args...)
+	if !errors.Is(err, ErrSocketUnavailable) {
+		return resp, err
+	}
+
+	bin, err := c.findBinary()
+	if err != nil {
+		return Response{}, err
+	}
+	return bin.Run(args...)
+}
+
+// ReloadConfig reloads the aerospace config.
+func (c *Client) ReloadConfig() error {
+	return reloadConfig(c)
+}
+
+// Monitor is a monitor as reported by `aerospace list-monitors --json`.
+type Monitor struct {
+	ID   int    `json:"monitor-id"`
+	Name string `json:"monitor-name"`
+}
+
+// FocusedMonitor returns the monitor that currently has focus.
+func (c *Client) FocusedMonitor() (Monitor, error) {
+	resp, err := c.Run("list-monitors", "--focused", "--json")
+	if err != nil {
+		return Monitor{}, err
+	}
+	if resp.ExitCode != 0 {
+		return Monitor{}, fmt.Errorf("aerospace list-monitors failed: %s", resp.Output())
+	}
+
+	var monitors []Monitor
+	if err := json.Unmarshal([]byte(resp.Stdout), &monitors); err != nil {
+		return Monitor{}, fmt.Errorf("parse aerospace list-monitors output: %w", err)
+	}
+	if len(monitors) == 0 {
+		return Monitor{}, errors.New("aerospace reported no focused monitor")
+	}
+	return monitors[0], nil
+}
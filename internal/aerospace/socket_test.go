@@ -0,0 +1,165 @@
+package aerospace
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// standIn is a fake aerospace server that records the commands it receives.
+type standIn struct {
+	path     string
+	response Response
+
+	mu       sync.Mutex
+	requests []socketRequest
+}
+
+func newStandIn(t *testing.T, response Response) *standIn {
+	t.Helper()
+
+	// Unix socket paths are length-limited, so avoid the long t.TempDir().
+	dir, err := os.MkdirTemp("", "aerospace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	s := &standIn{path: filepath.Join(dir, "s.sock"), response: response}
+	ln, err := net.Listen("unix", s.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			var req socketRequest
+			if err := json.NewDecoder(conn).Decode(&req); err == nil {
+				s.mu.Lock()
+				s.requests = append(s.requests, req)
+				s.mu.Unlock()
+				_ = json.NewEncoder(conn).Encode(s.response)
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	return s
+}
+
+func (s *standIn) commands() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var cmds [][]string
+	for _, req := range s.requests {
+		cmds = append(cmds, req.Args)
+	}
+	return cmds
+}
+
+func TestSocketRun(t *testing.T) {
+	server := newStandIn(t, Response{Stdout: "[]\n"})
+
+	resp, err := NewSocket(server.path).Run("list-monitors", "--json")
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if resp.Stdout != "[]\n" {
+		t.Errorf("Run() stdout = %q; want %q", resp.Stdout, "[]\n")
+	}
+
+	want := [][]string{{"list-monitors", "--json"}}
+	if got := server.commands(); !reflect.DeepEqual(got, want) {
+		t.Errorf("server received %v; want %v", got, want)
+	}
+}
+
+func TestClientReloadConfig(t *testing.T) {
+	server := newStandIn(t, Response{})
+	client := NewClientWithSocket(server.path)
+	client.findBinary = func() (*Binary, error) {
+		t.Fatal("binary used while the socket is available")
+		return nil, nil
+	}
+
+	if err := client.ReloadConfig(); err != nil {
+		t.Fatalf("ReloadConfig() error: %v", err)
+	}
+
+	want := [][]string{{"reload-config"}}
+	if got := server.commands(); !reflect.DeepEqual(got, want) {
+		t.Errorf("server received %v; want %v", got, want)
+	}
+}
+
+func TestClientReloadConfigFailure(t *testing.T) {
+	server := newStandIn(t, Response{ExitCode: 1, Stderr: "config is invalid\n"})
+
+	err := NewClientWithSocket(server.path).ReloadConfig()
+	if err == nil || !strings.Contains(err.Error(), "config is invalid") {
+		t.Errorf("ReloadConfig() error = %v; want the server's stderr", err)
+	}
+}
+
+func TestClientFallsBackToBinary(t *testing.T) {
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	script := "#!/bin/sh\necho \"$@\" >> " + calls + "\n"
+	bin := filepath.Join(dir, "aerospace")
+	if err := os.WriteFile(bin, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClientWithSocket(filepath.Join(dir, "missing.sock"))
+	client.findBinary = func() (*Binary, error) { return &Binary{path: bin}, nil }
+
+	if err := client.ReloadConfig(); err != nil {
+		t.Fatalf("ReloadConfig() error: %v", err)
+	}
+
+	data, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatalf("binary was not run: %v", err)
+	}
+	if string(data) != "reload-config\n" {
+		t.Errorf("binary args = %q; want %q", data, "reload-config\n")
+	}
+}
+
+func TestClientNotFound(t *testing.T) {
+	client := NewClientWithSocket(filepath.Join(t.TempDir(), "missing.sock"))
+	client.findBinary = func() (*Binary, error) { return nil, ErrNotFound }
+
+	if err := client.ReloadConfig(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ReloadConfig() error = %v; want ErrNotFound", err)
+	}
+}
+
+func TestClientFocusedMonitor(t *testing.T) {
+	server := newStandIn(t, Response{Stdout: `[{"monitor-id": 2, "monitor-name": "DELL U2722D"}]`})
+
+	got, err := NewClientWithSocket(server.path).FocusedMonitor()
+	if err != nil {
+		t.Fatalf("FocusedMonitor() error: %v", err)
+	}
+	if want := (Monitor{ID: 2, Name: "DELL U2722D"}); got != want {
+		t.Errorf("FocusedMonitor() = %+v; want %+v", got, want)
+	}
+
+	want := [][]string{{"list-monitors", "--focused", "--json"}}
+	if got := server.commands(); !reflect.DeepEqual(got, want) {
+		t.Errorf("server received %v; want %v", got, want)
+	}
+}
@@ -1,37 +1,75 @@
 // Package cli provides shared CLI types and utilities.
 package cli
 
-import ufcli "github.com/urfave/cli/v3"
+import (
+	"io"
+	"os"
+
+	"github.com/mholtzscher/aerospace-utils/internal/gaps"
+	"github.com/mholtzscher/aerospace-utils/internal/output"
+	ufcli "github.com/urfave/cli/v3"
+)
 
 // Flag names for global options.
 const (
-	FlagConfigPath   = "config-path"
-	FlagStatePath    = "state-path"
-	FlagMonitor      = "monitor"
-	FlagMonitorWidth = "monitor-width"
-	FlagNoReload     = "no-reload"
-	FlagDryRun       = "dry-run"
-	FlagVerbose      = "verbose"
-	FlagNoColor      = "no-color"
+	FlagConfigPath    = "config-path"
+	FlagStatePath     = "state-path"
+	FlagMonitor       = "monitor"
+	FlagMonitorWidth  = "monitor-width"
+	FlagMonitorHeight = "monitor-height"
+	FlagAll           = "all"
+	FlagNoReload      = "no-reload"
+	FlagCreate        = "create"
+	FlagDryRun        = "dry-run"
+	FlagVerbose       = "verbose"
+	FlagNoColor       = "no-color"
+	FlagOutput        = "output"
+	FlagRounding      = "rounding"
+	FlagBackups       = "backups"
+	FlagBackupDir     = "backup-dir"
+	FlagBaseConfig    = "base-config"
+	FlagRollback      = "rollback-on-reload-failure"
+)
+
+// Environment variables that set global options.
+const (
+	EnvBackups    = "AEROSPACE_UTILS_BACKUPS"
+	EnvBackupDir  = "AEROSPACE_UTILS_BACKUP_DIR"
+	EnvBaseConfig = "AEROSPACE_UTILS_BASE_CONFIG"
+	EnvRollback   = "AEROSPACE_UTILS_ROLLBACK_ON_RELOAD_FAILURE"
 )
 
 // GlobalOptions holds flags available to all subcommands.
 type GlobalOptions struct {
-	ConfigPath   string
-	StatePath    string
-	Monitor      string
-	MonitorWidth int64
-	NoReload     bool
-	DryRun       bool
-	Verbose      bool
-	NoColor      bool
+	ConfigPath    string
+	StatePath     string
+	Monitor       string
+	MonitorWidth  int64
+	MonitorHeight int64
+	All           bool
+	NoReload      bool
+	Create        bool
+	DryRun        bool
+	Verbose       bool
+	NoColor       bool
+	Output        output.Format
+	Rounding      gaps.Rounding
+	Backups       int    // backups kept of each file; 0 disables them
+	BackupDir     string // empty uses $XDG_STATE_HOME/aerospace-utils/backups
+	BaseConfig    string // read-only config rendered to ConfigPath; empty to edit ConfigPath
+	Rollback      bool   // restore config and state when the reload fails
+
+	// Stdout and Stderr receive the command's output. They are the root
+	// command's writers, so an in-process caller can capture them.
+	Stdout io.Writer
+	Stderr io.Writer
 }
 
 // GetOptions reads GlobalOptions from the root command's flags.
 // Call this in your command's Action to get the current values.
 func GetOptions(cmd *ufcli.Command) *GlobalOptions {
 	if cmd == nil {
-		return &GlobalOptions{}
+		return &GlobalOptions{Stdout: os.Stdout, Stderr: os.Stderr}
 	}
 
 	root := cmd.Root()
@@ -40,13 +78,51 @@ func GetOptions(cmd *ufcli.Command) *GlobalOptions {
 	}
 
 	return &GlobalOptions{
-		ConfigPath:   root.String(FlagConfigPath),
-		StatePath:    root.String(FlagStatePath),
-		Monitor:      root.String(FlagMonitor),
-		MonitorWidth: int64(root.Int(FlagMonitorWidth)),
-		NoReload:     root.Bool(FlagNoReload),
-		DryRun:       root.Bool(FlagDryRun),
-		Verbose:      root.Bool(FlagVerbose),
-		NoColor:      root.Bool(FlagNoColor),
+		ConfigPath:    root.String(FlagConfigPath),
+		StatePath:     root.String(FlagStatePath),
+		Monitor:       root.String(FlagMonitor),
+		MonitorWidth:  int64(root.Int(FlagMonitorWidth)),
+		MonitorHeight: int64(root.Int(FlagMonitorHeight)),
+		All:           root.Bool(FlagAll),
+		NoReload:      root.Bool(FlagNoReload),
+		Create:        root.Bool(FlagCreate),
+		DryRun:        root.Bool(FlagDryRun),
+		Verbose:       root.Bool(FlagVerbose),
+		NoColor:       root.Bool(FlagNoColor),
+		Output:        parseOutput(root.String(FlagOutput)),
+		Rounding:      parseRounding(root.String(FlagRounding)),
+		Backups:       int(root.Int(FlagBackups)),
+		BackupDir:     root.String(FlagBackupDir),
+		BaseConfig:    root.String(FlagBaseConfig),
+		Rollback:      root.Bool(FlagRollback),
+		Stdout:        writerOr(root.Writer, os.Stdout),
+		Stderr:        writerOr(root.ErrWriter, os.Stderr),
+	}
+}
+
+// parseOutput returns the output format, falling back to text. The flag's
+// validator rejects unknown values before commands run.
+func parseOutput(s string) output.Format {
+	format, err := output.ParseFormat(s)
+	if err != nil {
+		return output.FormatText
+	}
+	return format
+}
+
+// parseRounding returns the gap rounding strategy, falling back to nearest.
+func parseRounding(s string) gaps.Rounding {
+	rounding, err := gaps.ParseRounding(s)
+	if err != nil {
+		return gaps.RoundNearest
+	}
+	return rounding
+}
+
+// writerOr returns w, or fallback when w is nil.
+func writerOr(w, fallback io.Writer) io.Writer {
+	if w == nil {
+		return fallback
 	}
+	return w
 }
@@ -2,11 +2,12 @@
 package config
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -21,8 +22,12 @@ var (
 
 // AerospaceService abstracts config file resolution, loading, and writing.
 type AerospaceService struct {
-	configPath string
-	config     *aerospaceConfig // lazily loaded
+	configPath    string
+	createMissing bool
+	backups       Backups
+	basePath      string            // read-only config to render from; empty unless overlaid
+	managed       *WorkspaceService // state holding the gaps merged into basePath
+	config        *aerospaceConfig  // lazily loaded
 }
 
 // NewAerospaceService creates a service. If explicitPath is empty, uses DefaultConfigPath().
@@ -43,33 +48,59 @@ func (as *AerospaceService) ConfigPath() string {
 	return as.configPath
 }
 
+// SetCreateMissing controls whether updating a monitor that has no
+// gaps.outer.left/right entry creates one instead of returning ErrMonitorNotFound.
+func (as *AerospaceService) SetCreateMissing(create bool) {
+	as.createMissing = create
+}
+
+// SetBackups sets how the config is backed up before each write.
+func (as *AerospaceService) SetBackups(backups Backups) {
+	as.backups = backups
+}
+
 // loadConfig loads the config from disk if not already loaded.
 func (as *AerospaceService) loadConfig() error {
 	if as.config != nil {
 		return nil
 	}
 
-	content, err := os.ReadFile(as.configPath)
+	config, err := as.read()
 	if err != nil {
-		return fmt.Errorf("%w: %w", ErrConfigRead, err)
+		return err
 	}
+	as.config = config
 
-	var parsed map[string]any
-	if _, err := toml.Decode(string(content), &parsed); err != nil {
-		return fmt.Errorf("%w: %w", ErrConfigParse, err)
-	}
+	return nil
+}
 
-	as.config = &aerospaceConfig{
-		path:   as.configPath,
-		parsed: parsed,
+// read reads the config from disk, merging in the managed gaps in overlay
+// mode.
+func (as *AerospaceService) read() (*aerospaceConfig, error) {
+	content, loaded, err := readFile(as.SourcePath())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConfigRead, err)
 	}
 
-	return nil
+	config := &aerospaceConfig{path: as.configPath}
+	if as.basePath == "" {
+		config.loaded = &loaded
+	}
+	if err := config.setContent(content); err != nil {
+		return nil, err
+	}
+	if as.basePath != "" {
+		if err := config.mergeGaps(as.managed); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
 }
 
-// Exists returns true if the config file exists.
+// Exists returns true if the config file exists. In overlay mode it reports
+// whether the base config exists.
 func (as *AerospaceService) Exists() (bool, error) {
-	_, err := os.Stat(as.configPath)
+	_, err := os.Stat(as.SourcePath())
 	if err == nil {
 		return true, nil
 	}
@@ -93,6 +124,8 @@ type Summary struct {
 	OuterBottom     *int64
 	LeftGaps        []MonitorGap
 	RightGaps       []MonitorGap
+	TopGaps         []MonitorGap
+	BottomGaps      []MonitorGap
 }
 
 // Summary returns a summary of the gap configuration.
@@ -118,6 +151,8 @@ func (as *AerospaceService) Summary() (Summary, error) {
 		s.OuterBottom = extractScalarGap(outer["bottom"])
 		s.LeftGaps = extractMonitorGaps(outer["left"])
 		s.RightGaps = extractMonitorGaps(outer["right"])
+		s.TopGaps = extractMonitorGaps(outer["top"])
+		s.BottomGaps = extractMonitorGaps(outer["bottom"])
 	}
 
 	return s, nil
@@ -155,8 +190,14 @@ func (as *AerospaceService) SetMonitorGaps(monitorName string, gapSize int64) er
 		return err
 	}
 
-	leftUpdated := updateMonitorGapInConfig(as.config.parsed, "left", monitorName, gapSize)
-	rightUpdated := updateMonitorGapInConfig(as.config.parsed, "right", monitorName, gapSize)
+	leftUpdated, err := as.config.setMonitorGap("left", monitorName, gapSize, as.create())
+	if err != nil {
+		return err
+	}
+	rightUpdated, err := as.config.setMonitorGap("right", monitorName, gapSize, as.create())
+	if err != nil {
+		return err
+	}
 	if !leftUpdated && !rightUpdated {
 		return fmt.Errorf("%w: %s", ErrMonitorNotFound, monitorName)
 	}
@@ -170,8 +211,14 @@ func (as *AerospaceService) SetMonitorAsymmetricGaps(monitorName string, leftGap
 		return err
 	}
 
-	leftUpdated := updateMonitorGapInConfig(as.config.parsed, "left", monitorName, leftGap)
-	rightUpdated := updateMonitorGapInConfig(as.config.parsed, "right", monitorName, rightGap)
+	leftUpdated, err := as.config.setMonitorGap("left", monitorName, leftGap, as.create())
+	if err != nil {
+		return err
+	}
+	rightUpdated, err := as.config.setMonitorGap("right", monitorName, rightGap, as.create())
+	if err != nil {
+		return err
+	}
 	if !leftUpdated || !rightUpdated {
 		return fmt.Errorf("%w: %s", ErrMonitorNotFound, monitorName)
 	}
@@ -179,65 +226,112 @@ func (as *AerospaceService) SetMonitorAsymmetricGaps(monitorName string, leftGap
 	return nil
 }
 
-func updateMonitorGapInConfig(config map[string]any, side, monitorName string, gapSize int64) bool {
-	gaps, ok := config["gaps"].(map[string]any)
-	if !ok {
-		return false
-	}
-	outer, ok := gaps["outer"].(map[string]any)
-	if !ok {
-		return false
+// SetMonitorVerticalGaps updates both top and bottom gap values for a monitor.
+// Missing entries are always added, as they are for left and right with
+// create set: configs usually give top and bottom as one scalar for every
+// monitor, which becomes a per-monitor array keeping the scalar as fallback.
+func (as *AerospaceService) SetMonitorVerticalGaps(monitorName string, topGap, bottomGap int64) error {
+	if err := as.loadConfig(); err != nil {
+		return err
 	}
 
-	sideArray, ok := asAnySlice(outer[side])
-	if !ok {
-		return false
+	topUpdated, err := as.config.setMonitorGap("top", monitorName, topGap, true)
+	if err != nil {
+		return err
 	}
-
-	updated := false
-	for _, item := range sideArray {
-		m, ok := item.(map[string]any)
-		if !ok {
-			continue
-		}
-
-		monitor, ok := m["monitor"].(map[string]any)
-		if !ok {
-			continue
-		}
-
-		if _, exists := monitor[monitorName]; !exists {
-			continue
-		}
-
-		monitor[monitorName] = gapSize
-		updated = true
+	bottomUpdated, err := as.config.setMonitorGap("bottom", monitorName, bottomGap, true)
+	if err != nil {
+		return err
+	}
+	if !topUpdated || !bottomUpdated {
+		return fmt.Errorf("%w: %s (top/bottom)", ErrMonitorNotFound, monitorName)
 	}
 
-	return updated
+	return nil
 }
 
 // Write writes the config back to disk atomically.
+// Only the values changed through the service differ from the original file;
+// comments, key order and formatting are preserved. In overlay mode the base
+// config is rendered again with the gaps recorded in the state, which must
+// already include any new gaps, and written to the config path.
 func (as *AerospaceService) Write() error {
+	if as.basePath != "" {
+		if samePath(as.basePath, as.configPath) {
+			return fmt.Errorf("%w: %s is the base config; set the config path to a separate output file", ErrConfigWrite, as.configPath)
+		}
+		// Render afresh so the output depends only on the base and the
+		// recorded gaps, not on the order changes were made in.
+		config, err := as.read()
+		if err != nil {
+			return err
+		}
+		as.config = config
+	}
 	if as.config == nil {
 		return errors.New("no config loaded")
 	}
 
-	var buf bytes.Buffer
-	if err := toml.NewEncoder(&buf).Encode(as.config.parsed); err != nil {
-		return fmt.Errorf("encode config: %w", err)
+	if as.config.loaded != nil {
+		if err := as.config.loaded.check(as.config.path); err != nil {
+			return fmt.Errorf("%w: %w", ErrConfigWrite, err)
+		}
 	}
-
-	if err := WriteAtomic(as.config.path, buf.String()); err != nil {
+	if _, _, err := as.backups.Save(BackupConfig, as.config.path); err != nil {
+		return err
+	}
+	if err := WriteAtomic(as.config.path, string(as.config.content)); err != nil {
 		return fmt.Errorf("%w: %w", ErrConfigWrite, err)
 	}
+	if as.config.loaded != nil {
+		*as.config.loaded = fingerprintOf(as.config.path, as.config.content)
+	}
 	return nil
 }
 
 // aerospaceConfig holds the loaded config state.
 type aerospaceConfig struct {
-	path   string
-	parsed map[string]any
+	path    string
+	loaded  *fingerprint   // the file as loaded; nil when rendered from a base
+	content []byte         // raw file contents, edited in place
+	doc     *tomlDocument  // value locations within content
+	parsed  map[string]any // decoded view of content
+}
+
+// setContent replaces the raw config and refreshes the decoded views.
+func (c *aerospaceConfig) setContent(content []byte) error {
+	var parsed map[string]any
+	if _, err := toml.Decode(string(content), &parsed); err != nil {
+		return fmt.Errorf("%w: %w", ErrConfigParse, err)
+	}
+
+	doc, err := parseTOMLDocument(content)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrConfigParse, err)
+	}
+
+	c.content = content
+	c.doc = doc
+	c.parsed = parsed
+	return nil
+}
+
+// setMonitorGap rewrites the monitor's gaps.outer.<side> entries in place.
+// When create is set a missing entry is added; otherwise false is returned
+// if the monitor has no entry for that side.
+func (c *aerospaceConfig) setMonitorGap(side, monitorName string, value int64, create bool) (bool, error) {
+	edits := monitorGapEdits(c.doc, side, monitorName, value)
+	if len(edits) == 0 && create {
+		edits = monitorGapInsertEdits(c.doc, side, monitorName, value)
+	}
+	if len(edits) == 0 {
+		return false, nil
+	}
+
+	if err := c.setContent(applyTOMLEdits(c.content, edits)); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // extractInt64 extracts an int64 from an interface{} value.
@@ -274,7 +368,10 @@ func extractScalarGap(v any) *int64 {
 	return nil
 }
 
-// extractMonitorGaps extracts per-monitor gap values from an array.
+// extractMonitorGaps extracts per-monitor gap values from an array, in
+// document order. Monitors that share one table, such as
+// { monitor.a = 1, monitor.b = 2 }, are sorted by name, since the table is
+// decoded into a map.
 func extractMonitorGaps(v any) []MonitorGap {
 	arr, ok := asAnySlice(v)
 	if !ok {
@@ -293,9 +390,9 @@ func extractMonitorGaps(v any) []MonitorGap {
 			continue
 		}
 
-		for name, val := range monitor {
+		for _, name := range slices.Sorted(maps.Keys(monitor)) {
 			var value int64
-			switch v := val.(type) {
+			switch v := monitor[name].(type) {
 			case int64:
 				value = v
 			case float64:
@@ -334,50 +431,6 @@ func DefaultConfigPath() string {
 	return filepath.Join(home, ".config", "aerospace", "aerospace.toml")
 }
 
-// WriteAtomic writes content to a file atomically using a temporary file.
-func WriteAtomic(path, content string) error {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("create directory: %w", err)
-	}
-
-	// Create temp file in same directory for atomic rename.
-	tmp, err := os.CreateTemp(dir, ".tmp-*")
-	if err != nil {
-		return fmt.Errorf("create temp file: %w", err)
-	}
-	tmpPath := tmp.Name()
-
-	// Clean up temp file on error.
-	success := false
-	defer func() {
-		if !success {
-			if err := os.Remove(tmpPath); err != nil && !errors.Is(err, os.ErrNotExist) {
-				fmt.Fprintln(os.Stderr, "Failed to remove temp file:", err)
-			}
-		}
-	}()
-
-	if _, err := tmp.WriteString(content); err != nil {
-		if closeErr := tmp.Close(); closeErr != nil {
-			return fmt.Errorf("write temp file: %w; close temp file: %v", err, closeErr)
-		}
-		return fmt.Errorf("write temp file: %w", err)
-	}
-
-	if err := tmp.Close(); err != nil {
-		return fmt.Errorf("close temp file: %w", err)
-	}
-
-	// Atomic rename.
-	if err := os.Rename(tmpPath, path); err != nil {
-		return fmt.Errorf("rename temp file: %w", err)
-	}
-
-	success = true
-	return nil
-}
-
 // ExpandPath expands ~ to the home directory.
 func ExpandPath(path string) string {
 	if strings.HasPrefix(path, "~/") {
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSummaryMonitorGapsOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aerospace.toml")
+	content := "[gaps.outer]\n" +
+		"left = [{ monitor.zeta = 1, monitor.alpha = 2, monitor.mid = 3 }, { monitor.beta = 4 }, 10]\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 10 {
+		summary, err := NewAerospaceService(path).Summary()
+		if err != nil {
+			t.Fatalf("Summary() error: %v", err)
+		}
+		var names []string
+		for _, g := range summary.LeftGaps {
+			names = append(names, g.Name)
+		}
+		if want := []string{"alpha", "mid", "zeta", "beta"}; !slices.Equal(names, want) {
+			t.Fatalf("LeftGaps names = %v; want %v", names, want)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBackupNotFound = errors.New("backup not found")
+	ErrBackupWrite    = errors.New("failed to back up file")
+)
+
+// DefaultBackupKeep is the number of backups kept per kind by default.
+const DefaultBackupKeep = 10
+
+// backupTimeLayout formats the timestamp that starts a backup ID.
+const backupTimeLayout = "20060102-150405.000"
+
+// BackupKind identifies which file a backup is a copy of.
+type BackupKind string
+
+const (
+	BackupConfig BackupKind = "config"
+	BackupState  BackupKind = "state"
+)
+
+// Backup is one saved copy of the config or state file.
+type Backup struct {
+	ID   string // e.g. "20261016-162311.042-config"
+	Kind BackupKind
+	Time time.Time
+	Path string // the backup file
+	Size int64
+}
+
+// Backups keeps rotating, timestamped copies of files before they are
+// overwritten. The zero value keeps no backups.
+type Backups struct {
+	Dir  string // empty keeps each file's backups under DefaultBackupDir
+	Keep int    // backups kept per kind; 0 disables backups
+}
+
+// DirFor returns the directory that holds the backups of the file at path.
+func (b Backups) DirFor(path string) string {
+	if b.Dir != "" {
+		return ExpandPath(b.Dir)
+	}
+	return filepath.Join(DefaultBackupDir(), fileKey(path))
+}
+
+// DefaultBackupDir returns the directory that holds backups when no backup
+// directory is set: aerospace-utils/backups in $XDG_STATE_HOME, which
+// defaults to ~/.local/state. Each file's backups get their own
+// subdirectory, named from the file's resolved path.
+func DefaultBackupDir() string {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "aerospace-utils", "backups")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".local", "state", "aerospace-utils", "backups")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("aerospace-utils-%d", os.Getuid()), "backups")
+}
+
+// Save copies the file at path into the backup directory and removes the
+// oldest backups of the same kind beyond Keep. Nothing is saved when backups
+// are disabled, the file does not exist yet, or it is unchanged since the
+// newest backup; the returned bool reports whether a backup was made.
+func (b Backups) Save(kind BackupKind, path string) (Backup, bool, error) {
+	if b.Keep <= 0 {
+		return Backup{}, false, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Backup{}, false, nil
+	}
+	if err != nil {
+		return Backup{}, false, fmt.Errorf("%w: %w", ErrBackupWrite, err)
+	}
+
+	dir := b.DirFor(path)
+	existing, err := b.List(dir)
+	if err != nil {
+		return Backup{}, false, fmt.Errorf("%w: %w", ErrBackupWrite, err)
+	}
+	existing = slices.DeleteFunc(existing, func(bk Backup) bool { return bk.Kind != kind })
+	if len(existing) > 0 {
+		if newest, err := os.ReadFile(existing[0].Path); err == nil && bytes.Equal(newest, content) {
+			return Backup{}, false, nil
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return Backup{}, false, fmt.Errorf("%w: create directory: %w", ErrBackupWrite, err)
+	}
+
+	// IDs have millisecond precision; step past any backup made in the
+	// same millisecond, and past the newest one so IDs keep increasing
+	// even after older backups in that millisecond were rotated out.
+	stamp := time.Now()
+	if len(existing) > 0 && !stamp.After(existing[0].Time) {
+		stamp = existing[0].Time.Add(time.Millisecond)
+	}
+	var backup Backup
+	for {
+		backup = newBackup(dir, kind, stamp)
+		if _, err := os.Stat(backup.Path); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		stamp = stamp.Add(time.Millisecond)
+	}
+	if err := os.WriteFile(backup.Path, content, 0o600); err != nil {
+		return Backup{}, false, fmt.Errorf("%w: %w", ErrBackupWrite, err)
+	}
+	backup.Size = int64(len(content))
+
+	// existing is newest first; the new backup takes one of the slots.
+	for _, old := range existing[min(b.Keep-1, len(existing)):] {
+		if err := os.Remove(old.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return backup, true, fmt.Errorf("remove old backup %s: %w", old.ID, err)
+		}
+	}
+	return backup, true, nil
+}
+
+// List returns the backups in dirs, newest first. Missing directories and
+// files that are not backups are ignored.
+func (b Backups) List(dirs ...string) ([]Backup, error) {
+	var backups []Backup
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read backups: %w", err)
+		}
+
+		for _, entry := range entries {
+			backup, ok := parseBackup(dir, entry.Name())
+			if !ok || entry.IsDir() || slices.ContainsFunc(backups, func(bk Backup) bool { return bk.Path == backup.Path }) {
+				continue
+			}
+			if info, err := entry.Info(); err == nil {
+				backup.Size = info.Size()
+			}
+			backups = append(backups, backup)
+		}
+	}
+
+	slices.SortFunc(backups, func(a, b Backup) int {
+		if c := b.Time.Compare(a.Time); c != 0 {
+			return c
+		}
+		return strings.Compare(b.ID, a.ID)
+	})
+	return backups, nil
+}
+
+// Find returns the backup with the given ID from dirs.
+func (b Backups) Find(id string, dirs ...string) (Backup, error) {
+	backups, err := b.List(dirs...)
+	if err != nil {
+		return Backup{}, err
+	}
+	for _, backup := range backups {
+		if backup.ID == id {
+			return backup, nil
+		}
+	}
+	return Backup{}, fmt.Errorf("%w: %s", ErrBackupNotFound, id)
+}
+
+// Restore writes backup back to path, first backing up the file it replaces
+// so the restore can itself be undone.
+func (b Backups) Restore(backup Backup, path string) error {
+	content, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return fmt.Errorf("read backup %s: %w", backup.ID, err)
+	}
+	if _, _, err := b.Save(backup.Kind, path); err != nil {
+		return err
+	}
+	return WriteAtomic(path, string(content))
+}
+
+func newBackup(dir string, kind BackupKind, t time.Time) Backup {
+	id := t.Format(backupTimeLayout) + "-" + string(kind)
+	return Backup{
+		ID:   id,
+		Kind: kind,
+		Time: t.Truncate(time.Millisecond),
+		Path: filepath.Join(dir, id+".toml"),
+	}
+}
+
+// parseBackup parses a backup file name such as
+// "20261016-162311.042-config.toml".
+func parseBackup(dir, name string) (Backup, bool) {
+	id, ok := strings.CutSuffix(name, ".toml")
+	if !ok {
+		return Backup{}, false
+	}
+	i := strings.LastIndex(id, "-")
+	if i < 0 {
+		return Backup{}, false
+	}
+	kind := BackupKind(id[i+1:])
+	if kind != BackupConfig && kind != BackupState {
+		return Backup{}, false
+	}
+	t, err := time.ParseInLocation(backupTimeLayout, id[:i], time.Local)
+	if err != nil {
+		return Backup{}, false
+	}
+	return Backup{ID: id, Kind: kind, Time: t, Path: filepath.Join(dir, name)}, true
+}
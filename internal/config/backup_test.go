@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBackupsRotate(t *testing.T) {
+	stateHome := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateHome)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "aerospace.toml")
+	backups := Backups{Keep: 3}
+
+	for i := range 5 {
+		if err := os.WriteFile(path, fmt.Appendf(nil, "version = %d\n", i), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, saved, err := backups.Save(BackupConfig, path); err != nil || !saved {
+			t.Fatalf("Save() = %v, %v; want a backup", saved, err)
+		}
+	}
+
+	// An unchanged file is not backed up again.
+	if _, saved, err := backups.Save(BackupConfig, path); err != nil || saved {
+		t.Errorf("Save() of unchanged file = %v, %v; want no backup", saved, err)
+	}
+
+	list, err := backups.List(backups.DirFor(path))
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("len(List()) = %d; want 3", len(list))
+	}
+	if !strings.HasPrefix(list[0].Path, filepath.Join(stateHome, "aerospace-utils", "backups")+string(filepath.Separator)) {
+		t.Errorf("backup path = %q; want it under $XDG_STATE_HOME", list[0].Path)
+	}
+	for i, want := range []string{"version = 4\n", "version = 3\n", "version = 2\n"} {
+		got, err := os.ReadFile(list[i].Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("backup %d = %q; want %q", i, got, want)
+		}
+	}
+}
+
+func TestBackupsDisabled(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "aerospace.toml")
+	if err := os.WriteFile(path, []byte("x = 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, saved, err := (Backups{}).Save(BackupConfig, path); err != nil || saved {
+		t.Errorf("Save() = %v, %v; want no backup", saved, err)
+	}
+	if _, err := os.Stat(DefaultBackupDir()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("backup directory created with backups disabled")
+	}
+}
+
+func TestBackupsRestore(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.toml")
+	backups := Backups{Dir: filepath.Join(dir, "backups"), Keep: 1}
+
+	ws := NewWorkspaceService(path)
+	ws.SetBackups(backups)
+	for _, p := range []float64{50, 60} {
+		if err := ws.SetLayout("main", Layout{Current: p}, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := ws.Write(); err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+	}
+
+	list, err := backups.List(backups.DirFor(path))
+	if err != nil || len(list) != 1 {
+		t.Fatalf("List() = %v, %v; want one backup", list, err)
+	}
+	backup, err := backups.Find(list[0].ID, backups.DirFor(path))
+	if err != nil {
+		t.Fatalf("Find() error: %v", err)
+	}
+
+	// Keep is 1, so restoring rotates out the backup being restored.
+	if err := backups.Restore(backup, path); err != nil {
+		t.Fatalf("Restore() error: %v", err)
+	}
+	mon, err := NewWorkspaceService(path).GetMonitorState("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mon.Current == nil || *mon.Current != 50 {
+		t.Errorf("restored current = %v; want 50", mon.Current)
+	}
+
+	if _, err := backups.Find("20260101-000000.000-state", backups.DirFor(path)); !errors.Is(err, ErrBackupNotFound) {
+		t.Errorf("Find() error = %v; want ErrBackupNotFound", err)
+	}
+}
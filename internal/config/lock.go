@@ -0,0 +1,192 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var (
+	ErrLockTimeout = errors.New("timed out waiting for lock")
+	ErrConflict    = errors.New("file changed since it was loaded")
+)
+
+// LockTimeout is how long Lock waits for another process to finish.
+var LockTimeout = 5 * time.Second
+
+// lockPollInterval is how often a held lock is retried.
+const lockPollInterval = 10 * time.Millisecond
+
+// FileLock is an advisory lock that keeps other aerospace-utils processes
+// from loading and writing a file at the same time. It is held on a
+// separate lock file, since writes replace the file itself.
+type FileLock struct {
+	f *os.File // nil when the lock could not be taken, see LockFile
+}
+
+// LockPath returns the lock file used for path. Lock files are kept out of
+// the file's directory, which is often a dotfiles repository: they live in
+// $XDG_RUNTIME_DIR, or the user's cache directory when it is unset, under a
+// name derived from the file's resolved path.
+func LockPath(path string) string {
+	return filepath.Join(lockDir(), fileKey(path)+".lock")
+}
+
+// lockDir returns the directory holding lock files.
+func lockDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "aerospace-utils", "locks")
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "aerospace-utils", "locks")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("aerospace-utils-%d", os.Getuid()), "locks")
+}
+
+// fileKey names what is kept for path outside its directory, such as its
+// lock and backups: the file's base name and a hash of its resolved absolute path, so
+// every path that reaches the same file shares one key.
+func fileKey(path string) string {
+	path = ExpandPath(path)
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if target, err := resolveSymlinks(path); err == nil {
+		path = target
+	}
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+		path = filepath.Join(dir, filepath.Base(path))
+	}
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Base(path) + "-" + hex.EncodeToString(sum[:8])
+}
+
+// LockFile takes the exclusive lock for path, waiting up to LockTimeout for
+// another process to release it. Where the lock file cannot be created, such
+// as a read-only directory, an unheld lock is returned so commands still
+// run; the write reports any error with the file itself.
+func LockFile(path string) (*FileLock, error) {
+	lockPath := LockPath(path)
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o700); err != nil {
+		if isReadOnly(err) {
+			return &FileLock{}, nil
+		}
+		return nil, fmt.Errorf("create lock directory: %w", err)
+	}
+	f, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		if isReadOnly(err) {
+			return &FileLock{}, nil
+		}
+		return nil, fmt.Errorf("open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(LockTimeout)
+	for {
+		locked, err := tryLock(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("lock %s: %w", path, err)
+		}
+		if locked {
+			return &FileLock{f: f}, nil
+		}
+		if time.Now().After(deadline) {
+			_ = f.Close()
+			return nil, fmt.Errorf("%w on %s", ErrLockTimeout, path)
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// Unlock releases the lock.
+func (l *FileLock) Unlock() error {
+	if l == nil || l.f == nil {
+		return nil
+	}
+	// Closing the file releases the lock. The lock file is left in place:
+	// removing it would let two processes lock different files.
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
+
+// fingerprint identifies a file's content when it was loaded, to detect
+// changes made by other programs before it is written back.
+type fingerprint struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+	sum     [sha256.Size]byte
+}
+
+// readFile reads path and returns its fingerprint. The file is stat'ed
+// before it is read, so a change in between shows up as a conflict rather
+// than going unnoticed. A missing file has the zero fingerprint.
+func readFile(path string) ([]byte, fingerprint, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fingerprint{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fingerprint{}, err
+	}
+	return data, fingerprint{exists: true, modTime: info.ModTime(), size: info.Size(), sum: sha256.Sum256(data)}, nil
+}
+
+// fingerprintOf returns the fingerprint of path just after data was
+// written to it.
+func fingerprintOf(path string, data []byte) fingerprint {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fingerprint{}
+	}
+	return fingerprint{exists: true, modTime: info.ModTime(), size: info.Size(), sum: sha256.Sum256(data)}
+}
+
+// check returns ErrConflict if path no longer matches the fingerprint. The
+// modification time and size are compared first; when they differ the
+// content is hashed, so a file that was only touched is not a conflict.
+func (fp fingerprint) check(path string) error {
+	info, err := os.Stat(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if fp.exists {
+			return fmt.Errorf("%w: %s was removed", ErrConflict, path)
+		}
+		return nil
+	case err != nil:
+		return fmt.Errorf("stat %s: %w", path, err)
+	case !fp.exists:
+		return fmt.Errorf("%w: %s was created", ErrConflict, path)
+	case info.ModTime().Equal(fp.modTime) && info.Size() == fp.size:
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+	if sum := sha256.Sum256(data); !bytes.Equal(sum[:], fp.sum[:]) {
+		return fmt.Errorf("%w: %s", ErrConflict, path)
+	}
+	return nil
+}
+
+// Lock takes the lock on the config file, to hold across loading, changing
+// and writing it.
+func (as *AerospaceService) Lock() (*FileLock, error) {
+	return LockFile(as.configPath)
+}
+
+// Lock takes the lock on the state file, to hold across loading, changing
+// and writing it.
+func (ws *WorkspaceService) Lock() (*FileLock, error) {
+	return LockFile(ws.statePath)
+}
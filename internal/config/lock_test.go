@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteDetectsConflict(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.toml")
+	if err := os.WriteFile(path, []byte("[monitors.main]\ncurrent = 50.0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ws := NewWorkspaceService(path)
+	if err := ws.SetLayout("main", Layout{Current: 60}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process writes between our load and write.
+	other := "[monitors.main]\ncurrent = 70.0\n"
+	if err := os.WriteFile(path, []byte(other), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ws.Write(); !errors.Is(err, ErrConflict) {
+		t.Fatalf("Write() error = %v; want ErrConflict", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != other {
+		t.Errorf("state = %q; want the other write kept", got)
+	}
+}
+
+func TestWriteIgnoresTouch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aerospace.toml")
+	content := "[gaps.outer]\nleft = [{ monitor.main = 100 }]\nright = [{ monitor.main = 100 }]\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	as := NewAerospaceService(path)
+	if err := as.SetMonitorGaps("main", 200); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if err := as.Write(); err != nil {
+		t.Fatalf("Write() after touch error: %v", err)
+	}
+
+	// The service's own write is not a conflict for the next one.
+	if err := as.SetMonitorGaps("main", 300); err != nil {
+		t.Fatal(err)
+	}
+	if err := as.Write(); err != nil {
+		t.Fatalf("second Write() error: %v", err)
+	}
+}
+
+func TestLockPathOutsideFileDirectory(t *testing.T) {
+	runtime := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtime)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "aerospace.toml")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(t.TempDir(), "aerospace.toml")
+	if err := os.Symlink(path, link); err != nil {
+		t.Fatal(err)
+	}
+
+	got := LockPath(path)
+	if filepath.Dir(got) != filepath.Join(runtime, "aerospace-utils", "locks") {
+		t.Errorf("LockPath() = %q; want it under $XDG_RUNTIME_DIR", got)
+	}
+	if LockPath(link) != got {
+		t.Errorf("LockPath(symlink) = %q; want %q", LockPath(link), got)
+	}
+	if other := LockPath(filepath.Join(t.TempDir(), "aerospace.toml")); other == got {
+		t.Errorf("LockPath() of another file = %q; want a different lock", other)
+	}
+}
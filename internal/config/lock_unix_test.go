@@ -0,0 +1,38 @@
+//go:build unix
+
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLockFileWaits(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	path := filepath.Join(t.TempDir(), "state.toml")
+	held, err := LockFile(path)
+	if err != nil {
+		t.Fatalf("LockFile() error: %v", err)
+	}
+
+	prev := LockTimeout
+	LockTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { LockTimeout = prev })
+
+	if _, err := LockFile(path); !errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("LockFile() while held error = %v; want ErrLockTimeout", err)
+	}
+
+	if err := held.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	lock, err := LockFile(path)
+	if err != nil {
+		t.Fatalf("LockFile() after Unlock error: %v", err)
+	}
+	if err := lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+)
+
+// SetOverlay switches the service to overlay mode, for configs that cannot
+// be written such as one generated into the Nix store. The config is read
+// from basePath, the gaps recorded in state are merged into it, and Write
+// saves the result to the config path, which Aerospace is pointed at.
+// Missing monitor entries are always created, since the output is rendered
+// afresh from the base on every write.
+func (as *AerospaceService) SetOverlay(basePath string, state *WorkspaceService) {
+	as.basePath = ExpandPath(basePath)
+	as.managed = state
+	as.config = nil
+}
+
+// BasePath returns the base config path in overlay mode, or "".
+func (as *AerospaceService) BasePath() string {
+	return as.basePath
+}
+
+// SourcePath returns the file the config is read from: the base config in
+// overlay mode, otherwise the config path.
+func (as *AerospaceService) SourcePath() string {
+	if as.basePath != "" {
+		return as.basePath
+	}
+	return as.configPath
+}
+
+// Content returns the config as it would be written, including changes made
+// through the service and, in overlay mode, the merged gaps.
+func (as *AerospaceService) Content() ([]byte, error) {
+	if err := as.loadConfig(); err != nil {
+		return nil, err
+	}
+	return slices.Clone(as.config.content), nil
+}
+
+// create reports whether missing monitor entries should be added.
+func (as *AerospaceService) create() bool {
+	return as.createMissing || as.basePath != ""
+}
+
+// mergeGaps writes the gaps recorded in state into the config.
+func (c *aerospaceConfig) mergeGaps(state *WorkspaceService) error {
+	if state == nil {
+		return nil
+	}
+	monitors, err := state.Monitors()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(monitors)) {
+		gaps := monitors[name].Gaps
+		if gaps == nil {
+			continue
+		}
+		sides := []struct {
+			side  string
+			value *int64
+		}{
+			{"left", &gaps.Left},
+			{"right", &gaps.Right},
+			{"top", gaps.Top},
+			{"bottom", gaps.Bottom},
+		}
+		for _, s := range sides {
+			if s.value == nil {
+				continue
+			}
+			if _, err := c.setMonitorGap(s.side, name, *s.value, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// samePath reports whether a and b name the same file once symlinks are
+// followed.
+func samePath(a, b string) bool {
+	resolve := func(p string) string {
+		if target, err := resolveSymlinks(p); err == nil {
+			p = target
+		}
+		if abs, err := filepath.Abs(p); err == nil {
+			p = abs
+		}
+		return p
+	}
+	return resolve(a) == resolve(b)
+}
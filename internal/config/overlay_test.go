@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOverlayWrite(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.toml")
+	out := filepath.Join(dir, "out.toml")
+	baseContent := "[gaps.outer]\nleft = 10\nright = 10\n"
+	if err := os.WriteFile(base, []byte(baseContent), 0o444); err != nil {
+		t.Fatal(err)
+	}
+
+	ws := NewWorkspaceService(filepath.Join(dir, "state.toml"))
+	top := int64(40)
+	if err := ws.RecordGaps("main", AppliedGaps{Left: 300, Right: 200, Top: &top, Bottom: &top}); err != nil {
+		t.Fatal(err)
+	}
+	// Recording new horizontal gaps keeps the vertical ones.
+	if err := ws.RecordGaps("main", AppliedGaps{Left: 250, Right: 250}); err != nil {
+		t.Fatal(err)
+	}
+
+	as := NewAerospaceService(out)
+	as.SetOverlay(base, ws)
+	if err := as.Write(); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[gaps.outer]\nleft = [{ monitor.main = 250 }, 10]\nright = [{ monitor.main = 250 }, 10]\ntop = [{ monitor.main = 40 }]\nbottom = [{ monitor.main = 40 }]\n"
+	if string(got) != want {
+		t.Errorf("output =\n%s\nwant\n%s", got, want)
+	}
+	if got, _ := os.ReadFile(base); string(got) != baseContent {
+		t.Errorf("base changed to\n%s", got)
+	}
+}
+
+func TestOverlayRefusesBase(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.toml")
+	link := filepath.Join(dir, "aerospace.toml")
+	if err := os.WriteFile(base, []byte("[gaps.outer]\nleft = 10\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(base, link); err != nil {
+		t.Fatal(err)
+	}
+
+	as := NewAerospaceService(link)
+	as.SetOverlay(base, NewWorkspaceService(filepath.Join(dir, "state.toml")))
+	err := as.Write()
+	if !errors.Is(err, ErrConfigWrite) || !strings.Contains(err.Error(), "is the base config") {
+		t.Errorf("Write() error = %v; want the base config refused", err)
+	}
+}
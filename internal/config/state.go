@@ -6,29 +6,38 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/pelletier/go-toml/v2"
 )
 
 var (
-	ErrStateRead    = errors.New("failed to read state file")
-	ErrStateFormat  = errors.New("unrecognized state file format")
-	ErrStateMarshal = errors.New("failed to marshal state")
-	ErrStateWrite   = errors.New("failed to write state file")
+	ErrStateRead      = errors.New("failed to read state file")
+	ErrStateFormat    = errors.New("unrecognized state file format")
+	ErrStateMarshal   = errors.New("failed to marshal state")
+	ErrStateWrite     = errors.New("failed to write state file")
+	ErrNothingToUndo  = errors.New("nothing to undo")
+	ErrNothingToRedo  = errors.New("nothing to redo")
+	ErrPresetNotFound = errors.New("preset not found")
 )
 
-const defaultInitialPercentage int64 = 60
+const defaultInitialPercentage float64 = 60
+
+// MaxHistory is the number of previous layouts kept per monitor.
+const MaxHistory = 20
 
 // TOML keys for the state file.
 const (
 	StateKeyMonitors = "monitors"
 	StateKeyCurrent  = "current"
 	StateKeyDefault  = "default"
+	StateKeyPresets  = "presets"
 )
 
 // WorkspaceService abstracts state file resolution, loading, and writing.
 type WorkspaceService struct {
 	statePath string
+	backups   Backups
 	state     *workspaceState // lazily loaded
 }
 
@@ -50,6 +59,11 @@ func (ws *WorkspaceService) StatePath() string {
 	return ws.statePath
 }
 
+// SetBackups sets how the state file is backed up before each write.
+func (ws *WorkspaceService) SetBackups(backups Backups) {
+	ws.backups = backups
+}
+
 // Exists returns true if the state file exists.
 func (ws *WorkspaceService) Exists() (bool, error) {
 	_, err := os.Stat(ws.statePath)
@@ -71,9 +85,11 @@ func (ws *WorkspaceService) loadState() error {
 	state := &workspaceState{
 		path:     ws.statePath,
 		monitors: make(map[string]*MonitorState),
+		presets:  make(map[string]Preset),
 	}
 
-	data, err := os.ReadFile(ws.statePath)
+	data, loaded, err := readFile(ws.statePath)
+	state.loaded = loaded
 	if os.IsNotExist(err) {
 		ws.state = state
 		return nil
@@ -101,10 +117,15 @@ func (ws *WorkspaceService) loadState() error {
 	if err := toml.Unmarshal(data, &file); err != nil {
 		return ErrStateFormat
 	}
-	if _, ok := raw["monitors"]; ok {
+	_, hasMonitors := raw[StateKeyMonitors]
+	_, hasPresets := raw[StateKeyPresets]
+	if hasMonitors || hasPresets {
 		if file.Monitors != nil {
 			state.monitors = file.Monitors
 		}
+		if file.Presets != nil {
+			state.presets = file.Presets
+		}
 		ws.state = state
 		return nil
 	}
@@ -133,7 +154,7 @@ func (ws *WorkspaceService) getOrCreateMonitor(name string) *MonitorState {
 
 // ResolvePercentage returns the percentage to use for a monitor.
 // Priority: explicit > current > default, with a fallback when no state exists.
-func (ws *WorkspaceService) ResolvePercentage(monitor string, explicit *int64) (*int64, error) {
+func (ws *WorkspaceService) ResolvePercentage(monitor string, explicit *float64) (*float64, error) {
 	if err := ws.loadState(); err != nil {
 		return nil, err
 	}
@@ -158,33 +179,89 @@ func (ws *WorkspaceService) ResolvePercentage(monitor string, explicit *int64) (
 	return mon.Default, nil
 }
 
-// Update updates the percentage for a monitor and writes to disk.
-// Preserves existing shift value if any.
-func (ws *WorkspaceService) Update(monitor string, percentage int64, setDefault bool) error {
+// SetLayout sets the layout for a monitor in memory, recording the previous
+// layout in the monitor's history. Call Write to persist it.
+func (ws *WorkspaceService) SetLayout(monitor string, layout Layout, setDefault bool) error {
 	if err := ws.loadState(); err != nil {
 		return err
 	}
 
 	mon := ws.getOrCreateMonitor(monitor)
-	mon.Current = &percentage
+	mon.setLayout(layout, now())
 
 	if setDefault || mon.Default == nil {
+		percentage := layout.Current
 		mon.Default = &percentage
 	}
 
-	return ws.write()
+	return nil
 }
 
-// SetShift sets the shift value for a monitor and writes to disk.
-func (ws *WorkspaceService) SetShift(monitor string, shift int64) error {
+// RecordGaps saves the gaps written to the config for a monitor in memory.
+// Nil top and bottom gaps keep the recorded ones. Call Write to persist it.
+func (ws *WorkspaceService) RecordGaps(monitor string, gaps AppliedGaps) error {
 	if err := ws.loadState(); err != nil {
 		return err
 	}
 
 	mon := ws.getOrCreateMonitor(monitor)
-	mon.Shift = &shift
+	if mon.Gaps != nil && gaps.Top == nil {
+		gaps.Top, gaps.Bottom = mon.Gaps.Top, mon.Gaps.Bottom
+	}
+	mon.Gaps = &gaps
+	return nil
+}
 
-	return ws.write()
+// ReplaceLayout sets the layout for a monitor in memory without recording
+// the previous one, for re-applying the same layout on a changed display.
+// Call Write to persist it.
+func (ws *WorkspaceService) ReplaceLayout(monitor string, layout Layout) error {
+	if err := ws.loadState(); err != nil {
+		return err
+	}
+
+	ws.getOrCreateMonitor(monitor).restore(layout)
+	return nil
+}
+
+// Undo restores the monitor's previous layout in memory, making the current
+// layout available to Redo. Call Write to persist it.
+func (ws *WorkspaceService) Undo(monitor string) (HistoryEntry, error) {
+	if err := ws.loadState(); err != nil {
+		return HistoryEntry{}, err
+	}
+
+	mon := ws.state.monitors[monitor]
+	if mon == nil || len(mon.History) == 0 {
+		return HistoryEntry{}, fmt.Errorf("%w for %s", ErrNothingToUndo, monitor)
+	}
+
+	prev := mon.History[len(mon.History)-1]
+	mon.History = mon.History[:len(mon.History)-1]
+	mon.Redo = pushHistory(mon.Redo, mon.snapshot(now()))
+	mon.restore(prev.Layout())
+
+	return prev, nil
+}
+
+// Redo re-applies the layout most recently reverted by Undo in memory.
+// Call Write to persist it.
+func (ws *WorkspaceService) Redo(monitor string) (HistoryEntry, error) {
+	if err := ws.loadState(); err != nil {
+		return HistoryEntry{}, err
+	}
+
+	mon := ws.state.monitors[monitor]
+	if mon == nil || len(mon.Redo) == 0 {
+		return HistoryEntry{}, fmt.Errorf("%w for %s", ErrNothingToRedo, monitor)
+	}
+
+	next := mon.Redo[len(mon.Redo)-1]
+	mon.Redo = mon.Redo[:len(mon.Redo)-1]
+	mon.History = pushHistory(mon.History, mon.snapshot(now()))
+	mon.restore(next.Layout())
+
+	return next, nil
 }
 
 // GetShift returns the shift value for a monitor.
@@ -206,18 +283,63 @@ func (ws *WorkspaceService) GetShift(monitor string) (int64, error) {
 	return *mon.Shift, nil
 }
 
+// GetAlignment returns the edge a monitor's workspace is pinned to and its
+// margin in pixels. Returns "" if the workspace is centered.
+func (ws *WorkspaceService) GetAlignment(monitor string) (string, int64, error) {
+	if err := ws.loadState(); err != nil {
+		return "", 0, err
+	}
+
+	mon := ws.state.monitors[monitor]
+	if mon == nil || mon.Current == nil {
+		return "", 0, nil
+	}
+
+	return mon.Align, mon.Margin, nil
+}
+
+// GetSize returns the absolute size behind a monitor's current percentage.
+// Returns "" if the current layout is a plain percentage.
+func (ws *WorkspaceService) GetSize(monitor string) (string, error) {
+	if err := ws.loadState(); err != nil {
+		return "", err
+	}
+
+	mon := ws.state.monitors[monitor]
+	if mon == nil || mon.Current == nil {
+		return "", nil
+	}
+
+	return mon.Size, nil
+}
+
+// Write writes in-memory changes to disk.
+func (ws *WorkspaceService) Write() error {
+	if ws.state == nil {
+		return errors.New("no state loaded")
+	}
+	return ws.write()
+}
+
 // write writes the state to disk.
 func (ws *WorkspaceService) write() error {
-	file := stateFile{Monitors: ws.state.monitors}
+	file := stateFile{Monitors: ws.state.monitors, Presets: ws.state.presets}
 
 	data, err := toml.Marshal(file)
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrStateMarshal, err)
 	}
 
+	if err := ws.state.loaded.check(ws.state.path); err != nil {
+		return fmt.Errorf("%w: %w", ErrStateWrite, err)
+	}
+	if _, _, err := ws.backups.Save(BackupState, ws.state.path); err != nil {
+		return err
+	}
 	if err := WriteAtomic(ws.state.path, string(data)); err != nil {
 		return fmt.Errorf("%w: %w", ErrStateWrite, err)
 	}
+	ws.state.loaded = fingerprintOf(ws.state.path, data)
 	return nil
 }
 
@@ -229,22 +351,205 @@ func (ws *WorkspaceService) Monitors() (map[string]*MonitorState, error) {
 	return ws.state.monitors, nil
 }
 
+// Presets returns all saved presets by name.
+func (ws *WorkspaceService) Presets() (map[string]Preset, error) {
+	if err := ws.loadState(); err != nil {
+		return nil, err
+	}
+	return ws.state.presets, nil
+}
+
+// Preset returns the named preset.
+func (ws *WorkspaceService) Preset(name string) (Preset, error) {
+	if err := ws.loadState(); err != nil {
+		return nil, err
+	}
+
+	preset, ok := ws.state.presets[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPresetNotFound, name)
+	}
+	return preset, nil
+}
+
+// SavePreset stores a preset, replacing any preset with the same name, and writes to disk.
+func (ws *WorkspaceService) SavePreset(name string, preset Preset) error {
+	if err := ws.loadState(); err != nil {
+		return err
+	}
+
+	ws.state.presets[name] = preset
+	return ws.write()
+}
+
+// DeletePreset removes the named preset and writes to disk.
+func (ws *WorkspaceService) DeletePreset(name string) error {
+	if err := ws.loadState(); err != nil {
+		return err
+	}
+
+	if _, ok := ws.state.presets[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrPresetNotFound, name)
+	}
+	delete(ws.state.presets, name)
+	return ws.write()
+}
+
 // workspaceState holds per-monitor workspace percentages.
 type workspaceState struct {
 	path     string
+	loaded   fingerprint // the file as loaded, to detect changes before writing
 	monitors map[string]*MonitorState
+	presets  map[string]Preset
+}
+
+// Preset maps monitor names to the layout to apply on each.
+type Preset map[string]PresetLayout
+
+// PresetLayout is the percentage and shift a preset applies to a monitor.
+// Size, when set, is the absolute width the percentage was derived from.
+// Height is 0 for presets that leave the vertical gaps alone.
+type PresetLayout struct {
+	Current float64 `toml:"current"`
+	Shift   int64   `toml:"shift"`
+	Size    string  `toml:"size,omitempty"`
+	Align   string  `toml:"align,omitempty"`
+	Margin  int64   `toml:"margin,omitempty"`
+	Height  float64 `toml:"height,omitempty"`
+}
+
+// Layout returns the preset's layout for the monitor.
+func (p PresetLayout) Layout() Layout {
+	return Layout{Current: p.Current, Shift: p.Shift, Size: p.Size, Align: p.Align, Margin: p.Margin, Height: p.Height}
+}
+
+// Layout is how a workspace is placed on a monitor.
+type Layout struct {
+	Current float64 // percentage of the monitor width
+	Shift   int64   // percentage of the monitor width, positive to the right
+	Size    string  // absolute width such as "2560px"; empty for percentages
+	Align   string  // edge the workspace is pinned to; empty when centered
+	Margin  int64   // pixels between the workspace and the aligned edge
+	Height  float64 // percentage of the monitor height; 0 leaves it unmanaged
 }
 
 // MonitorState holds the current and default percentage for a monitor.
 type MonitorState struct {
-	Current *int64 `toml:"current,omitempty"`
-	Default *int64 `toml:"default,omitempty"`
-	Shift   *int64 `toml:"shift,omitempty"`
+	Current *float64       `toml:"current,omitempty"`
+	Default *float64       `toml:"default,omitempty"`
+	Shift   *int64         `toml:"shift,omitempty"`
+	Height  *float64       `toml:"height,omitempty"`
+	Size    string         `toml:"size,omitempty"` // absolute width such as "2560px"; empty for percentages
+	Align   string         `toml:"align,omitempty"`
+	Margin  int64          `toml:"margin,omitempty"`
+	Gaps    *AppliedGaps   `toml:"gaps,omitempty"`
+	History []HistoryEntry `toml:"history,omitempty"`
+	Redo    []HistoryEntry `toml:"redo,omitempty"`
+}
+
+// AppliedGaps are the gaps in pixels last written to the config for a
+// monitor. Overlay rendering merges them into the base config.
+type AppliedGaps struct {
+	Left   int64  `toml:"left"`
+	Right  int64  `toml:"right"`
+	Top    *int64 `toml:"top,omitempty"`
+	Bottom *int64 `toml:"bottom,omitempty"`
+}
+
+// HistoryEntry is a previously applied layout and the time it was replaced.
+type HistoryEntry struct {
+	Current float64   `toml:"current"`
+	Shift   int64     `toml:"shift"`
+	Size    string    `toml:"size,omitempty"`
+	Align   string    `toml:"align,omitempty"`
+	Margin  int64     `toml:"margin,omitempty"`
+	Height  *float64  `toml:"height,omitempty"`
+	Time    time.Time `toml:"time"`
+}
+
+// Layout returns the layout the entry recorded.
+func (e HistoryEntry) Layout() Layout {
+	layout := Layout{Current: e.Current, Shift: e.Shift, Size: e.Size, Align: e.Align, Margin: e.Margin}
+	if e.Height != nil {
+		layout.Height = *e.Height
+	}
+	return layout
+}
+
+// Layout returns the monitor's current layout. Current is 0 if unset.
+func (m *MonitorState) Layout() Layout {
+	var current float64
+	if m.Current != nil {
+		current = *m.Current
+	}
+	var height float64
+	if m.Height != nil {
+		height = *m.Height
+	}
+	return Layout{Current: current, Shift: m.shift(), Size: m.Size, Align: m.Align, Margin: m.Margin, Height: height}
+}
+
+// shift returns the stored shift, or 0 if unset.
+func (m *MonitorState) shift() int64 {
+	if m.Shift == nil {
+		return 0
+	}
+	return *m.Shift
+}
+
+// snapshot returns the current layout as a history entry replaced at now.
+func (m *MonitorState) snapshot(now time.Time) HistoryEntry {
+	l := m.Layout()
+	entry := HistoryEntry{Current: l.Current, Shift: l.Shift, Size: l.Size, Align: l.Align, Margin: l.Margin, Time: now}
+	if l.Height > 0 {
+		entry.Height = &l.Height
+	}
+	return entry
+}
+
+// setLayout applies a new layout, pushing the previous one onto the history
+// when it differs. Any redo entries are discarded.
+func (m *MonitorState) setLayout(layout Layout, now time.Time) {
+	if m.Current != nil && m.Layout() != layout {
+		m.History = pushHistory(m.History, m.snapshot(now))
+		m.Redo = nil
+	}
+	m.restore(layout)
+}
+
+// restore sets the current layout without touching the history stacks.
+func (m *MonitorState) restore(layout Layout) {
+	m.Current = &layout.Current
+	m.Size = layout.Size
+	m.Align = layout.Align
+	m.Margin = layout.Margin
+	m.Height = nil
+	if layout.Height > 0 {
+		m.Height = &layout.Height
+	}
+	if layout.Shift != 0 || m.Shift != nil {
+		m.Shift = &layout.Shift
+	}
+}
+
+// now returns the current time truncated to seconds for the state file.
+func now() time.Time {
+	return time.Now().Truncate(time.Second)
+}
+
+// pushHistory appends entry, dropping the oldest entries beyond MaxHistory.
+func pushHistory(stack []HistoryEntry, entry HistoryEntry) []HistoryEntry {
+	stack = append(stack, entry)
+	if len(stack) > MaxHistory {
+		stack = stack[len(stack)-MaxHistory:]
+	}
+	return stack
 }
 
 // stateFile is the TOML structure for the state file.
 type stateFile struct {
 	Monitors map[string]*MonitorState `toml:"monitors"`
+	Presets  map[string]Preset        `toml:"presets,omitempty"`
 }
 
 // DefaultStatePath returns the default path to the state file.
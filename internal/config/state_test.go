@@ -0,0 +1,188 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetLayoutHistoryIsBounded(t *testing.T) {
+	ws := NewWorkspaceService(filepath.Join(t.TempDir(), "state.toml"))
+
+	for p := 1.0; p <= MaxHistory+5; p++ {
+		if err := ws.SetLayout("main", Layout{Current: p}, false); err != nil {
+			t.Fatalf("SetLayout(%g) error: %v", p, err)
+		}
+	}
+
+	mon, err := ws.GetMonitorState("main")
+	if err != nil {
+		t.Fatalf("GetMonitorState() error: %v", err)
+	}
+	if len(mon.History) != MaxHistory {
+		t.Fatalf("len(History) = %d; want %d", len(mon.History), MaxHistory)
+	}
+	if got := mon.History[0].Current; got != 5 {
+		t.Errorf("oldest history entry = %g; want 5", got)
+	}
+}
+
+func TestSetLayoutSkipsUnchanged(t *testing.T) {
+	ws := NewWorkspaceService(filepath.Join(t.TempDir(), "state.toml"))
+
+	for range 3 {
+		if err := ws.SetLayout("main", Layout{Current: 60, Shift: 5}, false); err != nil {
+			t.Fatalf("SetLayout() error: %v", err)
+		}
+	}
+
+	mon, _ := ws.GetMonitorState("main")
+	if len(mon.History) != 0 {
+		t.Errorf("len(History) = %d; want 0", len(mon.History))
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.toml")
+	ws := NewWorkspaceService(path)
+
+	if err := ws.SetLayout("main", Layout{Current: 50}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := ws.SetLayout("main", Layout{Current: 60, Shift: 5}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := ws.Write(); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	// Reload from disk to exercise the persisted history.
+	ws = NewWorkspaceService(path)
+	prev, err := ws.Undo("main")
+	if err != nil {
+		t.Fatalf("Undo() error: %v", err)
+	}
+	if prev.Current != 50 || prev.Shift != 0 {
+		t.Errorf("Undo() = %+v; want 50%% with no shift", prev)
+	}
+	if _, err := ws.Undo("main"); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("second Undo() error = %v; want ErrNothingToUndo", err)
+	}
+
+	next, err := ws.Redo("main")
+	if err != nil {
+		t.Fatalf("Redo() error: %v", err)
+	}
+	if next.Current != 60 || next.Shift != 5 {
+		t.Errorf("Redo() = %+v; want 60%% shifted 5", next)
+	}
+
+	mon, _ := ws.GetMonitorState("main")
+	if *mon.Current != 60 || *mon.Shift != 5 {
+		t.Errorf("state after redo = %g/%d; want 60/5", *mon.Current, *mon.Shift)
+	}
+	if _, err := ws.Redo("main"); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("second Redo() error = %v; want ErrNothingToRedo", err)
+	}
+}
+
+func TestUndoRestoresSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.toml")
+	ws := NewWorkspaceService(path)
+
+	if err := ws.SetLayout("main", Layout{Current: 50, Size: "2560px"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := ws.SetLayout("main", Layout{Current: 60}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := ws.Write(); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	ws = NewWorkspaceService(path)
+	prev, err := ws.Undo("main")
+	if err != nil {
+		t.Fatalf("Undo() error: %v", err)
+	}
+	if prev.Size != "2560px" {
+		t.Errorf("Undo() size = %q; want 2560px", prev.Size)
+	}
+
+	mon, _ := ws.GetMonitorState("main")
+	if mon.Size != "2560px" || *mon.Current != 50 {
+		t.Errorf("state after undo = %g%% %q; want 50%% 2560px", *mon.Current, mon.Size)
+	}
+}
+
+func TestUndoRestoresAlignment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.toml")
+	ws := NewWorkspaceService(path)
+
+	if err := ws.SetLayout("main", Layout{Current: 60, Align: "right", Margin: 20}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := ws.SetLayout("main", Layout{Current: 60}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := ws.Write(); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	ws = NewWorkspaceService(path)
+	if _, err := ws.Undo("main"); err != nil {
+		t.Fatalf("Undo() error: %v", err)
+	}
+
+	align, margin, err := ws.GetAlignment("main")
+	if err != nil {
+		t.Fatalf("GetAlignment() error: %v", err)
+	}
+	if align != "right" || margin != 20 {
+		t.Errorf("alignment after undo = %q/%d; want right/20", align, margin)
+	}
+}
+
+func TestUndoRestoresHeight(t *testing.T) {
+	ws := NewWorkspaceService(filepath.Join(t.TempDir(), "state.toml"))
+
+	for _, height := range []float64{0, 80.5} {
+		if err := ws.SetLayout("main", Layout{Current: 60, Height: height}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := ws.Undo("main"); err != nil {
+		t.Fatalf("Undo() error: %v", err)
+	}
+	mon, _ := ws.GetMonitorState("main")
+	if mon.Height != nil {
+		t.Errorf("height after undo = %g; want unset", *mon.Height)
+	}
+
+	if _, err := ws.Redo("main"); err != nil {
+		t.Fatalf("Redo() error: %v", err)
+	}
+	if mon.Height == nil || *mon.Height != 80.5 {
+		t.Errorf("height after redo = %v; want 80.5", mon.Height)
+	}
+}
+
+func TestFractionalPercentageRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.toml")
+	ws := NewWorkspaceService(path)
+	if err := ws.SetLayout("main", Layout{Current: 62.5}, false); err != nil {
+		t.Fatalf("SetLayout() error: %v", err)
+	}
+	if err := ws.Write(); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	mon, err := NewWorkspaceService(path).GetMonitorState("main")
+	if err != nil {
+		t.Fatalf("GetMonitorState() error: %v", err)
+	}
+	if *mon.Current != 62.5 || *mon.Default != 62.5 {
+		t.Errorf("reloaded state = %g/%g; want 62.5/62.5", *mon.Current, *mon.Default)
+	}
+}
@@ -0,0 +1,573 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrTOMLSyntax indicates the config could not be scanned for in-place editing.
+var ErrTOMLSyntax = errors.New("invalid toml syntax")
+
+// tomlValueKind identifies the shape of a scanned TOML value.
+type tomlValueKind int
+
+const (
+	tomlScalar tomlValueKind = iota
+	tomlString
+	tomlArray
+	tomlInlineTable
+)
+
+// tomlValue records where a value lives in the source document.
+type tomlValue struct {
+	kind    tomlValueKind
+	start   int          // offset of the first byte of the value
+	end     int          // offset just past the last byte of the value
+	elems   []*tomlValue // array elements
+	entries []*tomlEntry // inline table entries, keyed relative to the table
+}
+
+// tomlEntry is a key/value pair with its key path.
+type tomlEntry struct {
+	path    []string
+	value   *tomlValue
+	header  int // number of leading path elements from the table header
+	lineEnd int // offset of the line end after a document-level entry
+}
+
+// tomlDocument is a position-aware view of a TOML file. It only tracks enough
+// structure to locate values so they can be rewritten without re-encoding the
+// rest of the file; validation is left to the real TOML decoder.
+type tomlDocument struct {
+	src     []byte
+	entries []*tomlEntry // document-level entries, keyed by full path
+	tables  []*tomlTable // explicit [table] headers in source order
+}
+
+// tomlTable records an explicit table header.
+type tomlTable struct {
+	path []string
+	end  int // offset of the line end after the table's last entry
+}
+
+// parseTOMLDocument scans src and records the location of every value.
+func parseTOMLDocument(src []byte) (*tomlDocument, error) {
+	s := &tomlScanner{src: src}
+	doc := &tomlDocument{src: src}
+
+	var table []string
+	var current *tomlTable
+	for {
+		s.skipBlank(true)
+		if s.eof() {
+			return doc, nil
+		}
+
+		if s.peek() == '[' {
+			header, err := s.tableHeader()
+			if err != nil {
+				return nil, err
+			}
+			table = header
+			current = &tomlTable{path: header}
+			doc.tables = append(doc.tables, current)
+		} else {
+			entry, err := s.keyValue()
+			if err != nil {
+				return nil, err
+			}
+			entry.path = append(slices.Clone(table), entry.path...)
+			entry.header = len(table)
+			doc.entries = append(doc.entries, entry)
+		}
+
+		// Anything after a header or value must be a comment or the line end.
+		s.skipBlank(false)
+		if !s.eof() && s.peek() != '\n' && s.peek() != '\r' {
+			return nil, s.errorf("unexpected %q after value", s.peek())
+		}
+		if current != nil {
+			current.end = s.pos
+		}
+		if n := len(doc.entries); n > 0 && doc.entries[n-1].lineEnd == 0 {
+			doc.entries[n-1].lineEnd = s.pos
+		}
+	}
+}
+
+// table returns the last explicit table header matching path.
+func (d *tomlDocument) table(path []string) *tomlTable {
+	for i := len(d.tables) - 1; i >= 0; i-- {
+		if slices.Equal(d.tables[i].path, path) {
+			return d.tables[i]
+		}
+	}
+	return nil
+}
+
+// lookup returns the value at path, descending into inline tables as needed.
+func (d *tomlDocument) lookup(path []string) *tomlValue {
+	return lookupEntries(d.entries, path)
+}
+
+func lookupEntries(entries []*tomlEntry, path []string) *tomlValue {
+	for _, e := range entries {
+		if slices.Equal(e.path, path) {
+			return e.value
+		}
+		if len(e.path) < len(path) && slices.Equal(e.path, path[:len(e.path)]) && e.value.kind == tomlInlineTable {
+			if v := lookupEntries(e.value.entries, path[len(e.path):]); v != nil {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// tomlEdit replaces src[start:end] with text.
+type tomlEdit struct {
+	start int
+	end   int
+	text  string
+}
+
+// applyTOMLEdits applies non-overlapping edits to src and returns the result.
+func applyTOMLEdits(src []byte, edits []tomlEdit) []byte {
+	slices.SortFunc(edits, func(a, b tomlEdit) int { return a.start - b.start })
+
+	var out []byte
+	last := 0
+	for _, e := range edits {
+		out = append(out, src[last:e.start]...)
+		out = append(out, e.text...)
+		last = e.end
+	}
+	return append(out, src[last:]...)
+}
+
+// monitorGapEdits returns edits that set every gaps.outer.<side> entry for
+// the named monitor to value. Entries may be written as `monitor.<name> = N`
+// or `monitor = { <name> = N }` inside the side array.
+func monitorGapEdits(doc *tomlDocument, side, monitorName string, value int64) []tomlEdit {
+	arr := doc.lookup([]string{"gaps", "outer", side})
+	if arr == nil || arr.kind != tomlArray {
+		return nil
+	}
+
+	var edits []tomlEdit
+	for _, elem := range arr.elems {
+		if elem.kind != tomlInlineTable {
+			continue
+		}
+		v := lookupEntries(elem.entries, []string{"monitor", monitorName})
+		if v == nil || v.kind != tomlScalar {
+			continue
+		}
+		edits = append(edits, tomlEdit{start: v.start, end: v.end, text: strconv.FormatInt(value, 10)})
+	}
+	return edits
+}
+
+// monitorGapInsertEdits returns edits that add a gaps.outer.<side> entry for
+// the named monitor. New entries go before any trailing scalar default so
+// Aerospace's first-match semantics keep applying the default last. A scalar
+// side value is upgraded to an array that keeps the scalar as the fallback.
+func monitorGapInsertEdits(doc *tomlDocument, side, monitorName string, value int64) []tomlEdit {
+	entry := fmt.Sprintf("{ monitor.%s = %d }", formatTOMLKey(monitorName), value)
+
+	v := doc.lookup([]string{"gaps", "outer", side})
+	switch {
+	case v == nil:
+		line := fmt.Sprintf("%s = [%s]", side, entry)
+		if t := doc.table([]string{"gaps", "outer"}); t != nil {
+			return []tomlEdit{{start: t.end, end: t.end, text: "\n" + line}}
+		}
+		// gaps.outer may be defined through dotted keys such as
+		// `outer.top = 10` under [gaps]; add the side next to them.
+		for i := len(doc.entries) - 1; i >= 0; i-- {
+			e := doc.entries[i]
+			if len(e.path) > 2 && e.path[0] == "gaps" && e.path[1] == "outer" && e.header < 2 {
+				var keys []string
+				for _, k := range append(slices.Clone(e.path[e.header:2]), side) {
+					keys = append(keys, formatTOMLKey(k))
+				}
+				text := fmt.Sprintf("\n%s = [%s]", strings.Join(keys, "."), entry)
+				return []tomlEdit{{start: e.lineEnd, end: e.lineEnd, text: text}}
+			}
+		}
+		prefix := "\n"
+		if len(doc.src) > 0 && doc.src[len(doc.src)-1] != '\n' {
+			prefix = "\n\n"
+		}
+		text := prefix + "[gaps.outer]\n" + line + "\n"
+		return []tomlEdit{{start: len(doc.src), end: len(doc.src), text: text}}
+	case v.kind == tomlArray:
+		return arrayInsertEdits(doc.src, v, entry)
+	default:
+		fallback := string(doc.src[v.start:v.end])
+		return []tomlEdit{{start: v.start, end: v.end, text: fmt.Sprintf("[%s, %s]", entry, fallback)}}
+	}
+}
+
+// arrayInsertEdits inserts elem into arr ahead of its trailing non-table
+// elements, matching the array's single- or multi-line layout.
+func arrayInsertEdits(src []byte, arr *tomlValue, elem string) []tomlEdit {
+	at := len(arr.elems)
+	for at > 0 && arr.elems[at-1].kind != tomlInlineTable {
+		at--
+	}
+
+	if at < len(arr.elems) {
+		next := arr.elems[at]
+		if lineStart, indent, ok := ownLine(src, next.start); ok {
+			return []tomlEdit{{start: lineStart, end: lineStart, text: indent + elem + ",\n"}}
+		}
+		return []tomlEdit{{start: next.start, end: next.start, text: elem + ", "}}
+	}
+
+	closing := arr.end - 1
+	if len(arr.elems) == 0 {
+		return []tomlEdit{{start: closing, end: closing, text: elem}}
+	}
+
+	last := arr.elems[len(arr.elems)-1]
+	lineStart, _, ok := ownLine(src, closing)
+	if !ok {
+		return []tomlEdit{{start: last.end, end: last.end, text: ", " + elem}}
+	}
+
+	_, indent, _ := ownLine(src, last.start)
+	edits := []tomlEdit{{start: lineStart, end: lineStart, text: indent + elem + ",\n"}}
+	if !followedByComma(src, last.end) {
+		edits = append(edits, tomlEdit{start: last.end, end: last.end, text: ","})
+	}
+	return edits
+}
+
+// ownLine reports whether only whitespace precedes pos on its line, returning
+// the line start offset and that leading whitespace.
+func ownLine(src []byte, pos int) (int, string, bool) {
+	start := pos
+	for start > 0 && (src[start-1] == ' ' || src[start-1] == '\t') {
+		start--
+	}
+	if start > 0 && src[start-1] != '\n' {
+		return 0, "", false
+	}
+	return start, string(src[start:pos]), true
+}
+
+// followedByComma reports whether the next token after pos is a comma.
+func followedByComma(src []byte, pos int) bool {
+	s := &tomlScanner{src: src, pos: pos}
+	s.skipBlank(true)
+	return !s.eof() && s.peek() == ','
+}
+
+// formatTOMLKey quotes key if it cannot be written as a bare key.
+func formatTOMLKey(key string) string {
+	bare := key != ""
+	for i := 0; i < len(key); i++ {
+		if !isBareKeyChar(key[i]) {
+			bare = false
+			break
+		}
+	}
+	switch {
+	case bare:
+		return key
+	case !strings.ContainsAny(key, "'\n\r"):
+		return "'" + key + "'"
+	default:
+		return strconv.Quote(key)
+	}
+}
+
+// tomlScanner walks TOML source keeping track of byte offsets.
+type tomlScanner struct {
+	src []byte
+	pos int
+}
+
+func (s *tomlScanner) eof() bool {
+	return s.pos >= len(s.src)
+}
+
+func (s *tomlScanner) peek() byte {
+	return s.src[s.pos]
+}
+
+func (s *tomlScanner) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(string(s.src[s.pos:]), prefix)
+}
+
+func (s *tomlScanner) errorf(format string, a ...any) error {
+	line := 1 + strings.Count(string(s.src[:min(s.pos, len(s.src))]), "\n")
+	return fmt.Errorf("%w: line %d: %s", ErrTOMLSyntax, line, fmt.Sprintf(format, a...))
+}
+
+// skipBlank skips spaces, tabs and comments, and newlines when multiline is set.
+func (s *tomlScanner) skipBlank(multiline bool) {
+	for !s.eof() {
+		switch c := s.peek(); {
+		case c == ' ' || c == '\t':
+			s.pos++
+		case multiline && (c == '\n' || c == '\r'):
+			s.pos++
+		case c == '#':
+			for !s.eof() && s.peek() != '\n' {
+				s.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// tableHeader scans a `[table]` or `[[array.of.tables]]` header.
+func (s *tomlScanner) tableHeader() ([]string, error) {
+	closing := "]"
+	s.pos++
+	if !s.eof() && s.peek() == '[' {
+		closing = "]]"
+		s.pos++
+	}
+
+	s.skipBlank(false)
+	path, err := s.key()
+	if err != nil {
+		return nil, err
+	}
+	s.skipBlank(false)
+	if !s.hasPrefix(closing) {
+		return nil, s.errorf("unterminated table header")
+	}
+	s.pos += len(closing)
+	return path, nil
+}
+
+// keyValue scans `key = value`.
+func (s *tomlScanner) keyValue() (*tomlEntry, error) {
+	path, err := s.key()
+	if err != nil {
+		return nil, err
+	}
+	s.skipBlank(false)
+	if s.eof() || s.peek() != '=' {
+		return nil, s.errorf("expected '=' after key")
+	}
+	s.pos++
+	s.skipBlank(false)
+
+	value, err := s.value()
+	if err != nil {
+		return nil, err
+	}
+	return &tomlEntry{path: path, value: value}, nil
+}
+
+// key scans a possibly dotted key made of bare and quoted parts.
+func (s *tomlScanner) key() ([]string, error) {
+	var path []string
+	for {
+		part, err := s.simpleKey()
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, part)
+
+		s.skipBlank(false)
+		if s.eof() || s.peek() != '.' {
+			return path, nil
+		}
+		s.pos++
+		s.skipBlank(false)
+	}
+}
+
+func (s *tomlScanner) simpleKey() (string, error) {
+	if s.eof() {
+		return "", s.errorf("expected key")
+	}
+
+	switch s.peek() {
+	case '"', '\'':
+		start := s.pos
+		if err := s.skipString(); err != nil {
+			return "", err
+		}
+		return unquoteTOMLString(string(s.src[start:s.pos])), nil
+	}
+
+	start := s.pos
+	for !s.eof() && isBareKeyChar(s.peek()) {
+		s.pos++
+	}
+	if start == s.pos {
+		return "", s.errorf("unexpected %q in key", s.peek())
+	}
+	return string(s.src[start:s.pos]), nil
+}
+
+// value scans any TOML value.
+func (s *tomlScanner) value() (*tomlValue, error) {
+	if s.eof() {
+		return nil, s.errorf("expected value")
+	}
+
+	start := s.pos
+	switch s.peek() {
+	case '"', '\'':
+		if err := s.skipString(); err != nil {
+			return nil, err
+		}
+		return &tomlValue{kind: tomlString, start: start, end: s.pos}, nil
+	case '[':
+		return s.array()
+	case '{':
+		return s.inlineTable()
+	}
+
+	for !s.eof() && !isScalarTerminator(s.peek()) {
+		s.pos++
+		// Local date-times may separate date and time with a single space.
+		if s.pos-start == 10 && s.hasPrefix(" ") && s.pos+1 < len(s.src) && isDigit(s.src[s.pos+1]) && isDigit(s.src[start]) {
+			s.pos++
+		}
+	}
+	if start == s.pos {
+		return nil, s.errorf("unexpected %q in value", s.peek())
+	}
+	return &tomlValue{kind: tomlScalar, start: start, end: s.pos}, nil
+}
+
+func (s *tomlScanner) array() (*tomlValue, error) {
+	v := &tomlValue{kind: tomlArray, start: s.pos}
+	s.pos++
+
+	for {
+		s.skipBlank(true)
+		if s.eof() {
+			return nil, s.errorf("unterminated array")
+		}
+		if s.peek() == ']' {
+			s.pos++
+			v.end = s.pos
+			return v, nil
+		}
+
+		elem, err := s.value()
+		if err != nil {
+			return nil, err
+		}
+		v.elems = append(v.elems, elem)
+
+		s.skipBlank(true)
+		if s.eof() {
+			return nil, s.errorf("unterminated array")
+		}
+		switch s.peek() {
+		case ',':
+			s.pos++
+		case ']':
+		default:
+			return nil, s.errorf("expected ',' or ']' in array")
+		}
+	}
+}
+
+func (s *tomlScanner) inlineTable() (*tomlValue, error) {
+	v := &tomlValue{kind: tomlInlineTable, start: s.pos}
+	s.pos++
+
+	for {
+		s.skipBlank(true)
+		if s.eof() {
+			return nil, s.errorf("unterminated inline table")
+		}
+		if s.peek() == '}' {
+			s.pos++
+			v.end = s.pos
+			return v, nil
+		}
+
+		entry, err := s.keyValue()
+		if err != nil {
+			return nil, err
+		}
+		v.entries = append(v.entries, entry)
+
+		s.skipBlank(true)
+		if s.eof() {
+			return nil, s.errorf("unterminated inline table")
+		}
+		switch s.peek() {
+		case ',':
+			s.pos++
+		case '}':
+		default:
+			return nil, s.errorf("expected ',' or '}' in inline table")
+		}
+	}
+}
+
+// skipString skips a basic, literal or multi-line string.
+func (s *tomlScanner) skipString() error {
+	quote := s.peek()
+	delim := string(quote)
+	if s.hasPrefix(strings.Repeat(delim, 3)) {
+		delim = strings.Repeat(delim, 3)
+	}
+	multiline := len(delim) == 3
+	s.pos += len(delim)
+
+	for !s.eof() {
+		c := s.peek()
+		switch {
+		case c == '\\' && quote == '"':
+			s.pos += 2
+		case c == '\n' && !multiline:
+			return s.errorf("unterminated string")
+		case s.hasPrefix(delim):
+			s.pos += len(delim)
+			// A multi-line string may end with up to two extra quotes.
+			for i := 0; multiline && i < 2 && !s.eof() && s.peek() == quote; i++ {
+				s.pos++
+			}
+			return nil
+		default:
+			s.pos++
+		}
+	}
+	return s.errorf("unterminated string")
+}
+
+// unquoteTOMLString returns the contents of a single-line quoted key.
+func unquoteTOMLString(quoted string) string {
+	if strings.HasPrefix(quoted, "'") {
+		return strings.Trim(quoted, "'")
+	}
+	if s, err := strconv.Unquote(quoted); err == nil {
+		return s
+	}
+	return strings.Trim(quoted, `"`)
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || isDigit(c) || c == '_' || c == '-'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isScalarTerminator(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', ',', ']', '}', '#':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,296 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMonitorGapEdits(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		side    string
+		monitor string
+		value   int64
+		want    string
+	}{
+		{
+			name: "outer table with dotted monitor keys",
+			src: `# gaps
+[gaps.outer]
+top = 10 # keep me
+left = [
+    { monitor.main = 100 },  # main display
+    { monitor.'Built-in Retina Display' = 200 },
+    24,
+]
+right = [{ monitor.main = 100 }, 24]
+`,
+			side:    "left",
+			monitor: "main",
+			value:   384,
+			want: `# gaps
+[gaps.outer]
+top = 10 # keep me
+left = [
+    { monitor.main = 384 },  # main display
+    { monitor.'Built-in Retina Display' = 200 },
+    24,
+]
+right = [{ monitor.main = 100 }, 24]
+`,
+		},
+		{
+			name: "quoted monitor name",
+			src: `[gaps.outer]
+left = [{ monitor."LG UltraFine" = 150 }]
+`,
+			side:    "left",
+			monitor: "LG UltraFine",
+			value:   42,
+			want: `[gaps.outer]
+left = [{ monitor."LG UltraFine" = 42 }]
+`,
+		},
+		{
+			name: "dotted key under gaps table",
+			src: `[gaps]
+outer.right = [ { monitor.main = 1 } ]
+`,
+			side:    "right",
+			monitor: "main",
+			value:   7,
+			want: `[gaps]
+outer.right = [ { monitor.main = 7 } ]
+`,
+		},
+		{
+			name: "nested inline tables",
+			src: `gaps = { outer = { left = [{ monitor = { main = 5 } }] } }
+`,
+			side:    "left",
+			monitor: "main",
+			value:   50,
+			want: `gaps = { outer = { left = [{ monitor = { main = 50 } }] } }
+`,
+		},
+		{
+			name: "every matching entry is updated",
+			src: `[gaps.outer]
+left = [{ monitor.main = 1 }, { monitor.main = 2 }]
+`,
+			side:    "left",
+			monitor: "main",
+			value:   3,
+			want: `[gaps.outer]
+left = [{ monitor.main = 3 }, { monitor.main = 3 }]
+`,
+		},
+		{
+			name: "strings and other tables are left alone",
+			src: `after-startup-command = ["exec-and-forget echo '[gaps.outer]'"]
+[mode.main.binding]
+alt-h = "focus left" # comment with = and [brackets]
+[gaps.outer]
+left = [{ monitor.main = 1 }]
+`,
+			side:    "left",
+			monitor: "main",
+			value:   9,
+			want: `after-startup-command = ["exec-and-forget echo '[gaps.outer]'"]
+[mode.main.binding]
+alt-h = "focus left" # comment with = and [brackets]
+[gaps.outer]
+left = [{ monitor.main = 9 }]
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parseTOMLDocument([]byte(tt.src))
+			if err != nil {
+				t.Fatalf("parseTOMLDocument() error: %v", err)
+			}
+
+			edits := monitorGapEdits(doc, tt.side, tt.monitor, tt.value)
+			if len(edits) == 0 {
+				t.Fatal("monitorGapEdits() found no entries")
+			}
+
+			got := string(applyTOMLEdits(doc.src, edits))
+			if got != tt.want {
+				t.Errorf("edited config mismatch\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonitorGapEditsNotFound(t *testing.T) {
+	src := `[gaps.outer]
+left = [{ monitor.main = 1 }, 10]
+right = 10
+`
+	doc, err := parseTOMLDocument([]byte(src))
+	if err != nil {
+		t.Fatalf("parseTOMLDocument() error: %v", err)
+	}
+
+	if edits := monitorGapEdits(doc, "left", "secondary", 5); len(edits) != 0 {
+		t.Errorf("expected no edits for unknown monitor, got %d", len(edits))
+	}
+	if edits := monitorGapEdits(doc, "right", "main", 5); len(edits) != 0 {
+		t.Errorf("expected no edits for scalar side, got %d", len(edits))
+	}
+}
+
+func TestParseTOMLDocumentErrors(t *testing.T) {
+	tests := []string{
+		"[gaps\n",
+		"left = [1, 2\n",
+		"key = \"unterminated\n",
+		"key value\n",
+		"key = 1 2\n",
+	}
+
+	for _, src := range tests {
+		if _, err := parseTOMLDocument([]byte(src)); !errors.Is(err, ErrTOMLSyntax) {
+			t.Errorf("parseTOMLDocument(%q) error = %v; want ErrTOMLSyntax", src, err)
+		}
+	}
+}
+
+func TestMonitorGapInsertEdits(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		monitor string
+		want    string
+	}{
+		{
+			name: "multi-line array before trailing default",
+			src: `[gaps.outer]
+left = [
+    { monitor.main = 100 }, # desk
+    24,
+]
+`,
+			monitor: "secondary",
+			want: `[gaps.outer]
+left = [
+    { monitor.main = 100 }, # desk
+    { monitor.secondary = 50 },
+    24,
+]
+`,
+		},
+		{
+			name: "multi-line array without default or trailing comma",
+			src: `[gaps.outer]
+left = [
+    { monitor.main = 100 }
+]
+`,
+			monitor: "Dell U2722D",
+			want: `[gaps.outer]
+left = [
+    { monitor.main = 100 },
+    { monitor.'Dell U2722D' = 50 },
+]
+`,
+		},
+		{
+			name: "single-line array before trailing default",
+			src: `[gaps.outer]
+left = [{ monitor.main = 100 }, 24]
+`,
+			monitor: "secondary",
+			want: `[gaps.outer]
+left = [{ monitor.main = 100 }, { monitor.secondary = 50 }, 24]
+`,
+		},
+		{
+			name: "single-line array without default",
+			src: `[gaps.outer]
+left = [{ monitor.main = 100 }]
+`,
+			monitor: "secondary",
+			want: `[gaps.outer]
+left = [{ monitor.main = 100 }, { monitor.secondary = 50 }]
+`,
+		},
+		{
+			name: "empty array",
+			src: `[gaps.outer]
+left = []
+`,
+			monitor: "main",
+			want: `[gaps.outer]
+left = [{ monitor.main = 50 }]
+`,
+		},
+		{
+			name: "scalar is kept as fallback",
+			src: `[gaps.outer]
+left = 10 # everywhere
+`,
+			monitor: "main",
+			want: `[gaps.outer]
+left = [{ monitor.main = 50 }, 10] # everywhere
+`,
+		},
+		{
+			name: "missing side in outer table",
+			src: `[gaps.outer]
+top = 10 # top
+bottom = 10
+
+[mode.main.binding]
+`,
+			monitor: "main",
+			want: `[gaps.outer]
+top = 10 # top
+bottom = 10
+left = [{ monitor.main = 50 }]
+
+[mode.main.binding]
+`,
+		},
+		{
+			name: "missing side with dotted outer keys",
+			src: `[gaps]
+outer.top = 10
+`,
+			monitor: "main",
+			want: `[gaps]
+outer.top = 10
+outer.left = [{ monitor.main = 50 }]
+`,
+		},
+		{
+			name:    "missing outer table",
+			src:     `start-at-login = true`,
+			monitor: "main",
+			want: `start-at-login = true
+
+[gaps.outer]
+left = [{ monitor.main = 50 }]
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parseTOMLDocument([]byte(tt.src))
+			if err != nil {
+				t.Fatalf("parseTOMLDocument() error: %v", err)
+			}
+
+			edits := monitorGapInsertEdits(doc, "left", tt.monitor, 50)
+			got := string(applyTOMLEdits(doc.src, edits))
+			if got != tt.want {
+				t.Errorf("edited config mismatch\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Transaction writes the changes staged in the config and state services
+// together. Changes are made through the services as usual; Commit then
+// writes aerospace.toml followed by the state file and, if the state cannot
+// be written, puts aerospace.toml back as it was, so the two files never
+// disagree. A committed transaction can still be rolled back, for when
+// Aerospace rejects the new config.
+type Transaction struct {
+	configSvc *AerospaceService
+	stateSvc  *WorkspaceService
+	saved     []savedFile // both files as they were before Commit; nil until committed
+}
+
+// savedFile is a file's content from before a transaction wrote it.
+type savedFile struct {
+	path    string
+	content []byte
+	exists  bool
+}
+
+// NewTransaction creates a transaction over the config and state services.
+func NewTransaction(configSvc *AerospaceService, stateSvc *WorkspaceService) *Transaction {
+	return &Transaction{configSvc: configSvc, stateSvc: stateSvc}
+}
+
+// Commit writes the config and then the state. If the state write fails,
+// the config is restored and the error says whether that worked.
+func (tx *Transaction) Commit() error {
+	if tx.saved != nil {
+		return errors.New("transaction already committed")
+	}
+
+	configFile, err := saveFile(tx.configSvc.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+	stateFile, err := saveFile(tx.stateSvc.StatePath())
+	if err != nil {
+		return fmt.Errorf("read state: %w", err)
+	}
+
+	if err := tx.configSvc.Write(); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	if err := tx.stateSvc.Write(); err != nil {
+		if restoreErr := configFile.restore(); restoreErr != nil {
+			return fmt.Errorf("write state: %w; %v", err, restoreErr)
+		}
+		return fmt.Errorf("write state: %w (aerospace.toml left unchanged)", err)
+	}
+
+	tx.saved = []savedFile{configFile, stateFile}
+	return nil
+}
+
+// Rollback puts both files back as they were before Commit.
+func (tx *Transaction) Rollback() error {
+	if tx.saved == nil {
+		return errors.New("transaction not committed")
+	}
+
+	var errs []error
+	for _, f := range tx.saved {
+		if err := f.restore(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	tx.saved = nil
+	return errors.Join(errs...)
+}
+
+// saveFile reads the file at path so it can be restored later.
+func saveFile(path string) (savedFile, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return savedFile{path: path}, nil
+	}
+	if err != nil {
+		return savedFile{}, err
+	}
+	return savedFile{path: path, content: content, exists: true}, nil
+}
+
+// restore writes the saved content back, removing the file if it did not
+// exist before.
+func (f savedFile) restore() error {
+	if !f.exists {
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("restore %s: %w", f.path, err)
+		}
+		return nil
+	}
+	if err := WriteAtomic(f.path, string(f.content)); err != nil {
+		return fmt.Errorf("restore %s: %w", f.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const transactionConfig = "[gaps.outer]\nleft = [{ monitor.main = 100 }]\nright = [{ monitor.main = 100 }]\n"
+
+// newTransactionServices returns services over a config and a state file
+// in a temporary directory, with a layout change staged in both.
+func newTransactionServices(t *testing.T, state string) (*AerospaceService, *WorkspaceService) {
+	t.Helper()
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "aerospace.toml")
+	statePath := filepath.Join(dir, "state.toml")
+	if err := os.WriteFile(configPath, []byte(transactionConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if state != "" {
+		if err := os.WriteFile(statePath, []byte(state), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	as := NewAerospaceService(configPath)
+	ws := NewWorkspaceService(statePath)
+	if err := as.SetMonitorGaps("main", 384); err != nil {
+		t.Fatal(err)
+	}
+	if err := ws.SetLayout("main", Layout{Current: 60}, false); err != nil {
+		t.Fatal(err)
+	}
+	return as, ws
+}
+
+func TestTransactionCommitAndRollback(t *testing.T) {
+	as, ws := newTransactionServices(t, "")
+
+	tx := NewTransaction(as, ws)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() error: %v", err)
+	}
+	if got, _ := os.ReadFile(as.ConfigPath()); !strings.Contains(string(got), "main = 384") {
+		t.Errorf("config = %q; want the new gaps", got)
+	}
+	if _, err := os.Stat(ws.StatePath()); err != nil {
+		t.Errorf("state not written: %v", err)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback() error: %v", err)
+	}
+	if got, _ := os.ReadFile(as.ConfigPath()); string(got) != transactionConfig {
+		t.Errorf("config after Rollback() = %q; want %q", got, transactionConfig)
+	}
+	if _, err := os.Stat(ws.StatePath()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("state after Rollback() exists; want it removed as before")
+	}
+}
+
+func TestTransactionRestoresConfigWhenStateFails(t *testing.T) {
+	as, ws := newTransactionServices(t, "[monitors.main]\ncurrent = 50.0\n")
+
+	// The state file changes after it was loaded, so writing it fails.
+	if err := os.WriteFile(ws.StatePath(), []byte("[monitors.main]\ncurrent = 70.0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := NewTransaction(as, ws).Commit()
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("Commit() error = %v; want ErrConflict", err)
+	}
+	if got, _ := os.ReadFile(as.ConfigPath()); string(got) != transactionConfig {
+		t.Errorf("config = %q; want it restored to %q", got, transactionConfig)
+	}
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// ErrReadOnly indicates the file to write, or its directory, cannot be
+// written, such as a config that lives in the Nix store.
+var ErrReadOnly = errors.New("file is read-only")
+
+// defaultFileMode is the mode of files that did not exist before.
+const defaultFileMode fs.FileMode = 0o644
+
+// maxSymlinks bounds symlink resolution, as the kernel does.
+const maxSymlinks = 40
+
+// WriteAtomic writes content to a file atomically using a temporary file.
+//
+// A symlinked path is written through to its target, so dotfile managers
+// keep their links. An existing file keeps its mode and, where permitted,
+// its owner. The file and its directory are synced before returning. Writing
+// a read-only file returns ErrReadOnly rather than replacing it.
+func WriteAtomic(path, content string) error {
+	target, err := resolveSymlinks(path)
+	if err != nil {
+		return err
+	}
+
+	mode := defaultFileMode
+	info, err := os.Stat(target)
+	switch {
+	case err == nil:
+		if info.Mode().Perm()&0o200 == 0 {
+			return readOnlyError(target, nil)
+		}
+		mode = info.Mode().Perm()
+	case !errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("stat %s: %w", target, err)
+	}
+
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		if isReadOnly(err) {
+			return readOnlyError(target, err)
+		}
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	// Create temp file in same directory for atomic rename.
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		if isReadOnly(err) {
+			return readOnlyError(target, err)
+		}
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	// Clean up temp file on error.
+	success := false
+	defer func() {
+		if !success {
+			if err := os.Remove(tmpPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+				fmt.Fprintln(os.Stderr, "Failed to remove temp file:", err)
+			}
+		}
+	}()
+
+	if err := writeTemp(tmp, content, mode, info); err != nil {
+		if closeErr := tmp.Close(); closeErr != nil {
+			return fmt.Errorf("%w; close temp file: %v", err, closeErr)
+		}
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file: %w", err)
+	}
+
+	// Atomic rename.
+	if err := os.Rename(tmpPath, target); err != nil {
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+	success = true
+
+	if err := syncDir(dir); err != nil {
+		return fmt.Errorf("sync directory: %w", err)
+	}
+	return nil
+}
+
+// writeTemp writes content to tmp with the target's mode and owner (from
+// info, nil for a new file) and syncs it to disk.
+func writeTemp(tmp *os.File, content string, mode fs.FileMode, info fs.FileInfo) error {
+	if _, err := tmp.WriteString(content); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		return fmt.Errorf("set file mode: %w", err)
+	}
+	if info != nil {
+		if err := chownLike(tmp, info); err != nil {
+			return fmt.Errorf("set file owner: %w", err)
+		}
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("sync temp file: %w", err)
+	}
+	return nil
+}
+
+// resolveSymlinks follows path through any symlinks to the file they point
+// at. A link to a file that does not exist yet resolves to that file.
+func resolveSymlinks(path string) (string, error) {
+	for range maxSymlinks {
+		info, err := os.Lstat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return path, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("stat %s: %w", path, err)
+		}
+		if info.Mode()&fs.ModeSymlink == 0 {
+			return path, nil
+		}
+
+		link, err := os.Readlink(path)
+		if err != nil {
+			return "", fmt.Errorf("read symlink %s: %w", path, err)
+		}
+		if !filepath.IsAbs(link) {
+			link = filepath.Join(filepath.Dir(path), link)
+		}
+		path = link
+	}
+	return "", fmt.Errorf("resolve %s: too many levels of symbolic links", path)
+}
+
+// syncDir flushes a directory entry change, such as a rename, to disk.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	if err := d.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+		_ = d.Close()
+		return err
+	}
+	return d.Close()
+}
+
+// isReadOnly reports whether err comes from writing to a read-only file
+// system or a location without write permission.
+func isReadOnly(err error) bool {
+	return errors.Is(err, syscall.EROFS) || errors.Is(err, fs.ErrPermission)
+}
+
+// readOnlyError describes why target cannot be written.
+func readOnlyError(target string, cause error) error {
+	hint := ""
+	if strings.HasPrefix(target, "/nix/store/") {
+		hint = " (it is in the Nix store; change it through your Nix configuration or use a writable path)"
+	}
+	if cause != nil {
+		return fmt.Errorf("%w: %s%s: %w", ErrReadOnly, target, hint, cause)
+	}
+	return fmt.Errorf("%w: %s%s", ErrReadOnly, target, hint)
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteAtomicFollowsSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles", "aerospace.toml")
+	link := filepath.Join(dir, "aerospace.toml")
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("old\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("dotfiles", "aerospace.toml"), link); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteAtomic(link, "new\n"); err != nil {
+		t.Fatalf("WriteAtomic() error: %v", err)
+	}
+
+	info, err := os.Lstat(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("%s was replaced; want it to stay a symlink", link)
+	}
+	got, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "new\n" {
+		t.Errorf("target content = %q; want %q", got, "new\n")
+	}
+	if info, _ := os.Stat(target); info.Mode().Perm() != 0o640 {
+		t.Errorf("target mode = %v; want 0640", info.Mode().Perm())
+	}
+}
+
+func TestWriteAtomicDanglingSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "state.toml")
+	link := filepath.Join(dir, "link.toml")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteAtomic(link, "x = 1\n"); err != nil {
+		t.Fatalf("WriteAtomic() error: %v", err)
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		t.Fatalf("target not created: %v", err)
+	}
+	if info.Mode().Perm() != defaultFileMode {
+		t.Errorf("new file mode = %v; want %v", info.Mode().Perm(), defaultFileMode)
+	}
+}
+
+func TestWriteAtomicReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aerospace.toml")
+	if err := os.WriteFile(path, []byte("old\n"), 0o444); err != nil {
+		t.Fatal(err)
+	}
+
+	err := WriteAtomic(path, "new\n")
+	if !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("WriteAtomic() error = %v; want ErrReadOnly", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "old\n" {
+		t.Errorf("content = %q; want the file left alone", got)
+	}
+}
@@ -0,0 +1,51 @@
+package control
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	dialTimeout    = time.Second
+	requestTimeout = 30 * time.Second
+)
+
+// Client sends requests to a running server.
+type Client struct {
+	path string
+}
+
+// NewClient returns a client for the socket at path.
+func NewClient(path string) *Client {
+	return &Client{path: path}
+}
+
+// Run sends req to the server. It returns ErrUnavailable when nothing is
+// listening, or the socket belongs to another user, so callers can run the
+// command themselves.
+func (c *Client) Run(req Request) (Response, error) {
+	if err := checkOwner(c.path); err != nil {
+		return Response{}, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	conn, err := net.DialTimeout("unix", c.path, dialTimeout)
+	if err != nil {
+		return Response{}, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if err := conn.SetDeadline(time.Now().Add(requestTimeout)); err != nil {
+		return Response{}, fmt.Errorf("server socket: %w", err)
+	}
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return Response{}, fmt.Errorf("send to server: %w", err)
+	}
+
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return Response{}, fmt.Errorf("read from server: %w", err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,50 @@
+// Package control implements the local control API: a long-running server
+// that runs workspace commands in-process for thin clients, over a Unix
+// socket and optionally over localhost HTTP.
+package control
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+)
+
+// EnvSocket overrides the path of the server's Unix socket.
+const EnvSocket = "AEROSPACE_UTILS_SERVER_SOCKET"
+
+// ErrUnavailable indicates no server is listening on the socket.
+var ErrUnavailable = errors.New("aerospace-utils server unavailable")
+
+// DefaultSocketPath returns the socket the server listens on, honoring
+// EnvSocket.
+func DefaultSocketPath() string {
+	if path := os.Getenv(EnvSocket); path != "" {
+		return path
+	}
+
+	name := os.Getenv("USER")
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	return fmt.Sprintf("/tmp/aerospace-utils-%s.sock", name)
+}
+
+// Request is a command line for the server to run.
+type Request struct {
+	Args []string `json:"args"`          // arguments after the program name
+	Dir  string   `json:"dir,omitempty"` // working directory for relative paths
+}
+
+// Response is the outcome of a Request: what the command printed and the
+// error it returned, if any.
+type Response struct {
+	Stdout string `json:"stdout"`
+	Stderr string `json:"stderr"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Runner runs a command line, writing its output to stdout and stderr.
+type Runner func(ctx context.Context, args []string, stdout, stderr io.Writer) error
@@ -0,0 +1,283 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// recorder is a Runner that records the command lines it is given.
+type recorder struct {
+	calls [][]string
+	err   error
+}
+
+func (r *recorder) run(_ context.Context, args []string, stdout, stderr io.Writer) error {
+	r.calls = append(r.calls, args)
+	_, _ = fmt.Fprintf(stdout, "ran %s\n", strings.Join(args, " "))
+	_, _ = fmt.Fprintln(stderr, "warning")
+	return r.err
+}
+
+// socketPath returns a short socket path; Unix socket paths are
+// length-limited, so avoid the long t.TempDir().
+func socketPath(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "control")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return filepath.Join(dir, "s.sock")
+}
+
+func TestClientServer(t *testing.T) {
+	path := socketPath(t)
+	rec := &recorder{}
+	server := NewServer(rec.run)
+
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatalf("Listen() error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- server.Serve(ctx, ln) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	if _, err := Listen(path); err == nil {
+		t.Error("second Listen() succeeded; want an error for the running server")
+	}
+
+	resp, err := NewClient(path).Run(Request{Args: []string{"workspace", "use", "60"}})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	want := Response{Stdout: "ran workspace use 60\n", Stderr: "warning\n"}
+	if resp != want {
+		t.Errorf("Run() = %+v; want %+v", resp, want)
+	}
+
+	rec.err = errors.New("percentage must be between 1 and 100")
+	resp, err = NewClient(path).Run(Request{Args: []string{"workspace", "use", "600"}})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if resp.Error != rec.err.Error() {
+		t.Errorf("Run() error field = %q; want %q", resp.Error, rec.err)
+	}
+}
+
+func TestClientUnavailable(t *testing.T) {
+	_, err := NewClient(socketPath(t)).Run(Request{Args: []string{"workspace", "current"}})
+	if !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Run() error = %v; want ErrUnavailable", err)
+	}
+}
+
+func TestListenReplacesStaleSocket(t *testing.T) {
+	path := socketPath(t)
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatalf("Listen() error: %v", err)
+	}
+	_ = ln.Close()
+}
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		host        string // defaults to the listen address
+		contentType string
+		body        string
+		runErr      error
+		wantStatus  int
+		wantArgs    []string
+	}{
+		{
+			name:       "current",
+			method:     http.MethodGet,
+			path:       "/current",
+			wantStatus: http.StatusOK,
+			wantArgs:   []string{"workspace", "current", "--output", "json"},
+		},
+		{
+			name:        "use with number",
+			method:      http.MethodPost,
+			path:        "/use",
+			contentType: "application/json",
+			body:        `{"size": 62.5, "monitor": "focused", "set_default": true}`,
+			wantStatus:  http.StatusOK,
+			wantArgs:    []string{"workspace", "use", "--set-default", "--monitor=focused", "--output", "json", "--", "62.5"},
+		},
+		{
+			name:        "use with absolute size",
+			method:      http.MethodPost,
+			path:        "/use",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"size": "2560px", "all": true, "no_reload": true}`,
+			wantStatus:  http.StatusOK,
+			wantArgs:    []string{"workspace", "use", "--all", "--no-reload", "--output", "json", "--", "2560px"},
+		},
+		{
+			name:        "flag as size",
+			method:      http.MethodPost,
+			path:        "/use",
+			contentType: "application/json",
+			body:        `{"size": "--state-path=/tmp/evil.toml", "no_reload": true}`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "flag as monitor",
+			method:      http.MethodPost,
+			path:        "/adjust",
+			contentType: "application/json",
+			body:        `{"monitor": "--state-path=/tmp/evil.toml"}`,
+			wantStatus:  http.StatusOK,
+			wantArgs:    []string{"workspace", "adjust", "--monitor=--state-path=/tmp/evil.toml", "--output", "json"},
+		},
+		{
+			name:        "shift",
+			method:      http.MethodPost,
+			path:        "/shift",
+			contentType: "application/json",
+			body:        `{"by": -5, "dry_run": true}`,
+			wantStatus:  http.StatusOK,
+			wantArgs:    []string{"workspace", "shift", "--by=-5", "--dry-run", "--output", "json"},
+		},
+		{
+			name:        "command failure",
+			method:      http.MethodPost,
+			path:        "/adjust",
+			contentType: "application/json",
+			body:        `{"by": 50}`,
+			runErr:      errors.New("adjusted percentage 110% for main is invalid"),
+			wantStatus:  http.StatusUnprocessableEntity,
+			wantArgs:    []string{"workspace", "adjust", "--by=50", "--output", "json"},
+		},
+		{
+			name:       "form post",
+			method:     http.MethodPost,
+			path:       "/use",
+			body:       `{"size": 60}`,
+			wantStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "unknown field",
+			method:      http.MethodPost,
+			path:        "/use",
+			contentType: "application/json",
+			body:        `{"percent": 60}`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:       "localhost",
+			method:     http.MethodGet,
+			path:       "/current",
+			host:       "localhost:7878",
+			wantStatus: http.StatusOK,
+			wantArgs:   []string{"workspace", "current", "--output", "json"},
+		},
+		{
+			name:       "ipv6 loopback",
+			method:     http.MethodGet,
+			path:       "/current",
+			host:       "[::1]:7878",
+			wantStatus: http.StatusOK,
+			wantArgs:   []string{"workspace", "current", "--output", "json"},
+		},
+		{
+			name:        "foreign host",
+			method:      http.MethodPost,
+			path:        "/use",
+			host:        "evil.example:7878",
+			contentType: "application/json",
+			body:        `{"size": 60}`,
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			name:       "other port",
+			method:     http.MethodGet,
+			path:       "/current",
+			host:       "localhost:8080",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodGet,
+			path:       "/use",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{err: tt.runErr}
+			handler := NewServer(rec.run).Handler("127.0.0.1:7878")
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Host = "127.0.0.1:7878"
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d; want %d (body %q)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			var gotArgs []string
+			if len(rec.calls) > 0 {
+				gotArgs = rec.calls[0]
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("args = %q; want %q", gotArgs, tt.wantArgs)
+			}
+			if tt.runErr != nil && !strings.Contains(w.Body.String(), tt.runErr.Error()) {
+				t.Errorf("body = %q; want the command error", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCheckLoopback(t *testing.T) {
+	tests := []struct {
+		addr    string
+		wantErr bool
+	}{
+		{addr: "127.0.0.1:7878"},
+		{addr: "localhost:7878"},
+		{addr: "[::1]:7878"},
+		{addr: ":7878", wantErr: true},
+		{addr: "0.0.0.0:7878", wantErr: true},
+		{addr: "192.168.1.10:7878", wantErr: true},
+		{addr: "7878", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := CheckLoopback(tt.addr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CheckLoopback(%q) error = %v; wantErr %v", tt.addr, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package control
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mholtzscher/aerospace-utils/internal/gaps"
+)
+
+// layoutBody is the JSON body of the HTTP layout endpoints. Fields that do
+// not apply to an endpoint are ignored.
+type layoutBody struct {
+	Size       sizeValue `json:"size"`        // use: percentage or absolute width
+	By         *float64  `json:"by"`          // adjust, shift
+	Monitor    string    `json:"monitor"`     // defaults to main
+	All        bool      `json:"all"`         // every monitor in the config
+	SetDefault bool      `json:"set_default"` // use: also save as the default
+	DryRun     bool      `json:"dry_run"`
+	NoReload   bool      `json:"no_reload"`
+}
+
+// sizeValue accepts a JSON number (60) or string ("60", "2560px").
+type sizeValue string
+
+func (v *sizeValue) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*v = sizeValue(s)
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("size must be a number or string: %w", err)
+	}
+	*v = sizeValue(n)
+	return nil
+}
+
+// args returns the command line for a layout endpoint. Values from the body
+// are attached to their flags or placed after "--", so they cannot be read as
+// other flags.
+func (b layoutBody) args(command string) ([]string, error) {
+	args := []string{"workspace", command}
+	var positional []string
+	switch command {
+	case "use":
+		if b.Size != "" {
+			if _, err := gaps.ParseSize(string(b.Size)); err != nil {
+				return nil, err
+			}
+			positional = append(positional, string(b.Size))
+		}
+		if b.SetDefault {
+			args = append(args, "--set-default")
+		}
+	case "adjust", "shift":
+		if b.By != nil {
+			args = append(args, "--by="+strconv.FormatFloat(*b.By, 'f', -1, 64))
+		}
+	}
+
+	if b.Monitor != "" {
+		args = append(args, "--monitor="+b.Monitor)
+	}
+	if b.All {
+		args = append(args, "--all")
+	}
+	if b.DryRun {
+		args = append(args, "--dry-run")
+	}
+	if b.NoReload {
+		args = append(args, "--no-reload")
+	}
+	args = append(args, "--output", "json")
+	if len(positional) > 0 {
+		args = append(append(args, "--"), positional...)
+	}
+	return args, nil
+}
+
+// Handler returns the HTTP API:
+//
+//	GET  /current  the current layouts, as `workspace current --output json`
+//	POST /use      {"size": 60}
+//	POST /adjust   {"by": 5}
+//	POST /shift    {"by": -5}
+//
+// Layout endpoints also take monitor, all, dry_run and no_reload, and answer
+// with the command's JSON result. Failures are {"error": "..."}.
+//
+// addr is the address the API listens on. Requests must name it as
+// localhost, 127.0.0.1 or [::1] with its port in the Host header, so a web
+// page cannot reach the API by pointing its own domain at 127.0.0.1 (DNS
+// rebinding).
+func (s *Server) Handler(addr string) http.Handler {
+	_, port, _ := net.SplitHostPort(addr)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /current", func(w http.ResponseWriter, r *http.Request) {
+		s.serveCommand(w, r, []string{"workspace", "current", "--output", "json"})
+	})
+	for _, command := range []string{"use", "adjust", "shift"} {
+		mux.HandleFunc("POST /"+command, func(w http.ResponseWriter, r *http.Request) {
+			// Requiring JSON keeps plain HTML forms on other sites from
+			// posting here.
+			if ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); ct != "application/json" {
+				writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+				return
+			}
+
+			var body layoutBody
+			dec := json.NewDecoder(r.Body)
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&body); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+				return
+			}
+			args, err := body.args(command)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+				return
+			}
+			s.serveCommand(w, r, args)
+		})
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !localHost(r.Host, port) {
+			writeError(w, http.StatusForbidden, fmt.Sprintf("host %q not allowed", r.Host))
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// localHost reports whether a Host header names a loopback host with port.
+func localHost(hostHeader, port string) bool {
+	host, p, err := net.SplitHostPort(hostHeader)
+	if err != nil {
+		// No port given, which means port 80.
+		host, p = hostHeader, "80"
+	}
+	if p != port {
+		return false
+	}
+	switch strings.ToLower(host) {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	}
+	return false
+}
+
+func (s *Server) serveCommand(w http.ResponseWriter, r *http.Request, args []string) {
+	resp := s.Run(r.Context(), Request{Args: args})
+	if resp.Error != "" {
+		writeError(w, http.StatusUnprocessableEntity, resp.Error)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(resp.Stdout))
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	var buf bytes.Buffer
+	_ = json.NewEncoder(&buf).Encode(map[string]string{"error": message})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// CheckLoopback returns an error unless addr (host:port) is on the loopback
+// interface. The API has no authentication, so it must not be reachable from
+// other machines.
+func CheckLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("HTTP address %q must be on localhost (e.g. 127.0.0.1:7878)", addr)
+}
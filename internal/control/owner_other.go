@@ -0,0 +1,8 @@
+//go:build !unix
+
+package control
+
+// checkOwner accepts any socket where file ownership is not available.
+func checkOwner(string) error {
+	return nil
+}
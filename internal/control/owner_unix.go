@@ -0,0 +1,27 @@
+//go:build unix
+
+package control
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkOwner returns an error unless the socket at path belongs to the
+// current user, so commands are never sent to a server someone else
+// started at the expected path.
+func checkOwner(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	if uid := os.Getuid(); int(st.Uid) != uid {
+		return fmt.Errorf("%s is owned by uid %d, not %d", path, st.Uid, uid)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+//go:build unix
+
+package control
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestClientRejectsOtherUsersSocket(t *testing.T) {
+	path := socketPath(t)
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatalf("Listen() error: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	if err := os.Lchown(path, os.Getuid()+1, -1); err != nil {
+		t.Skipf("cannot change socket owner: %v", err)
+	}
+	_, err = NewClient(path).Run(Request{Args: []string{"workspace", "current"}})
+	if !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Run() error = %v; want ErrUnavailable", err)
+	}
+}
@@ -0,0 +1,126 @@
+package control
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server runs requests one at a time, so overlapping keypresses cannot
+// interleave their config and state writes.
+type Server struct {
+	run Runner
+
+	// Logf, when set, is called once for every request handled.
+	Logf func(format string, a ...any)
+
+	mu sync.Mutex
+}
+
+// NewServer returns a server that runs requests with run.
+func NewServer(run Runner) *Server {
+	return &Server{run: run}
+}
+
+// Run runs a request and returns its output. Requests are serialized, and
+// each runs in its own working directory when one is given.
+func (s *Server) Run(ctx context.Context, req Request) Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var stdout, stderr bytes.Buffer
+	err := s.runIn(ctx, req, &stdout, &stderr)
+
+	resp := Response{Stdout: stdout.String(), Stderr: stderr.String()}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+
+	if s.Logf != nil {
+		status := "ok"
+		if err != nil {
+			status = err.Error()
+		}
+		s.Logf("%s: %s\n", strings.Join(req.Args, " "), status)
+	}
+	return resp
+}
+
+func (s *Server) runIn(ctx context.Context, req Request, stdout, stderr *bytes.Buffer) error {
+	if req.Dir != "" {
+		prev, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("get working directory: %w", err)
+		}
+		if err := os.Chdir(req.Dir); err != nil {
+			return fmt.Errorf("change to %s: %w", req.Dir, err)
+		}
+		defer func() { _ = os.Chdir(prev) }()
+	}
+	return s.run(ctx, req.Args, stdout, stderr)
+}
+
+// Serve answers requests on ln until ctx is done: one JSON request per
+// connection, answered by one JSON response.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("accept: %w", err)
+		}
+		go s.handle(ctx, conn)
+	}
+}
+
+func (s *Server) handle(ctx context.Context, conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	if err := conn.SetReadDeadline(time.Now().Add(requestTimeout)); err != nil {
+		return
+	}
+	var req Request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		_ = json.NewEncoder(conn).Encode(Response{Error: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+
+	_ = json.NewEncoder(conn).Encode(s.Run(ctx, req))
+}
+
+// Listen listens on the Unix socket at path, readable only by the current
+// user. A socket left behind by a server that is no longer running is
+// replaced; a live one is an error.
+func Listen(path string) (net.Listener, error) {
+	if conn, err := net.DialTimeout("unix", path, dialTimeout); err == nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("a server is already listening on %s", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("remove stale socket: %w", err)
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		_ = ln.Close()
+		return nil, fmt.Errorf("restrict socket: %w", err)
+	}
+	return ln, nil
+}
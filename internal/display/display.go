@@ -1,10 +1,57 @@
 // Package display provides monitor/display detection functionality.
 package display
 
+import "os"
+
 // Info contains information about a display.
 type Info struct {
-	ID    uint32 // CoreGraphics display ID (macOS)
-	Name  string // Human-readable display name
-	Width int64  // Display width in pixels
-	Main  bool   // Whether this is the main/primary display
+	ID      uint32 // CoreGraphics display ID (macOS)
+	Name    string // Human-readable display name
+	Width   int64  // Display width in pixels
+	Height  int64  // Display height in pixels
+	WidthMM int64  // Physical width in millimeters, 0 if unknown
+	Main    bool   // Whether this is the main/primary display
+}
+
+// Provider detects the active displays.
+type Provider interface {
+	// Available reports whether the provider can detect displays.
+	Available() bool
+	// Enumerate returns information about all active displays.
+	Enumerate() ([]Info, error)
+}
+
+// EnvFixture names the environment variable that points at a fixture file
+// (JSON or TOML) to use instead of the platform's display detection.
+const EnvFixture = "AEROSPACE_UTILS_DISPLAYS"
+
+// override replaces the default provider when set.
+var override Provider
+
+// SetProvider replaces the display provider. Passing nil restores the
+// default: a FixtureProvider when EnvFixture is set, otherwise the platform's
+// own detection.
+func SetProvider(p Provider) {
+	override = p
+}
+
+// CurrentProvider returns the provider used by Enumerate and Available.
+func CurrentProvider() Provider {
+	if override != nil {
+		return override
+	}
+	if path := os.Getenv(EnvFixture); path != "" {
+		return FixtureProvider{Path: path}
+	}
+	return platformProvider{}
+}
+
+// Enumerate returns information about all active displays.
+func Enumerate() ([]Info, error) {
+	return CurrentProvider().Enumerate()
+}
+
+// Available returns true if display detection is supported.
+func Available() bool {
+	return CurrentProvider().Available()
 }
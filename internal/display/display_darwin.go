@@ -111,6 +111,8 @@ char* getDisplayName(CGDirectDisplayID displayID) {
 typedef struct {
     CGDirectDisplayID id;
     long width;
+    long height;
+    long widthMM;
     int isMain;
     char *name;
 } DisplayData;
@@ -132,6 +134,8 @@ int getDisplays(DisplayData *displays, int maxDisplays) {
     for (int i = 0; i < count; i++) {
         displays[i].id = displayIDs[i];
         displays[i].width = CGDisplayPixelsWide(displayIDs[i]);
+        displays[i].height = CGDisplayPixelsHigh(displayIDs[i]);
+        displays[i].widthMM = (long)CGDisplayScreenSize(displayIDs[i]).width;
         displays[i].isMain = (displayIDs[i] == mainID) ? 1 : 0;
         displays[i].name = getDisplayName(displayIDs[i]);
     }
@@ -155,8 +159,11 @@ import (
 	"unsafe"
 )
 
+// platformProvider detects displays with CoreGraphics.
+type platformProvider struct{}
+
 // Enumerate returns information about all active displays.
-func Enumerate() ([]Info, error) {
+func (platformProvider) Enumerate() ([]Info, error) {
 	var displays [16]C.DisplayData
 
 	count := C.getDisplays(&displays[0], 16)
@@ -168,31 +175,21 @@ func Enumerate() ([]Info, error) {
 	result := make([]Info, count)
 	for i := 0; i < int(count); i++ {
 		result[i] = Info{
-			ID:    uint32(displays[i].id),
-			Name:  C.GoString(displays[i].name),
-			Width: int64(displays[i].width),
-			Main:  displays[i].isMain != 0,
+			ID:      uint32(displays[i].id),
+			Name:    C.GoString(displays[i].name),
+			Width:   int64(displays[i].width),
+			Height:  int64(displays[i].height),
+			WidthMM: int64(displays[i].widthMM),
+			Main:    displays[i].isMain != 0,
 		}
 	}
 
 	return result, nil
 }
 
-// MainWidth returns the width of the main display in pixels.
-func MainWidth() (int64, error) {
-	width := C.CGDisplayPixelsWide(C.CGMainDisplayID())
-	if width == 0 {
-		return 0, errors.New("failed to get main display width")
-	}
-	return int64(width), nil
-}
-
-// available indicates whether display detection is available on this platform.
-const available = true
-
-// Available returns true if display detection is supported on this platform.
-func Available() bool {
-	return available
+// Available returns true; CoreGraphics is always present on macOS.
+func (platformProvider) Available() bool {
+	return true
 }
 
 // dummyUnsafe is used to ensure the unsafe package is imported for CGO.
@@ -3,83 +3,79 @@
 package display
 
 import (
-	"bufio"
-	"errors"
 	"fmt"
+	"os"
 	"os/exec"
-	"regexp"
-	"strconv"
 	"strings"
 )
 
-// xrandr output pattern: "DP-1 connected primary 2560x1440+0+0 ..."
-var connectedPattern = regexp.MustCompile(`^(\S+)\s+connected\s+(primary\s+)?(\d+)x(\d+)`)
-
-// Enumerate returns information about all active displays using xrandr.
-func Enumerate() ([]Info, error) {
-	cmd := exec.Command("xrandr", "--query")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("xrandr failed: %w (is xrandr installed?)", err)
-	}
-
-	var displays []Info
-	var primaryFound bool
-
-	scanner := bufio.NewScanner(strings.NewReader(string(output)))
-	for scanner.Scan() {
-		line := scanner.Text()
-		matches := connectedPattern.FindStringSubmatch(line)
-		if matches == nil {
-			continue
-		}
-
-		name := matches[1]
-		isPrimary := strings.TrimSpace(matches[2]) == "primary"
-		width, _ := strconv.ParseInt(matches[3], 10, 64)
+// tool is a command-line display query and the parser for its output.
+type tool struct {
+	name  string
+	args  []string
+	parse func([]byte) ([]Info, error)
+}
 
-		if isPrimary {
-			primaryFound = true
-		}
+var (
+	swaymsg  = tool{name: "swaymsg", args: []string{"-t", "get_outputs", "--raw"}, parse: parseSwayOutputs}
+	hyprctl  = tool{name: "hyprctl", args: []string{"monitors", "-j"}, parse: parseHyprlandMonitors}
+	wlrRandr = tool{name: "wlr-randr", parse: parseWlrRandr}
+	xrandr   = tool{name: "xrandr", args: []string{"--query"}, parse: parseXrandr}
+)
 
-		displays = append(displays, Info{
-			ID:    uint32(len(displays)),
-			Name:  name,
-			Width: width,
-			Main:  isPrimary,
-		})
+// sessionTools returns the display tools to try for the session described by
+// getenv, most specific first. Compositor-specific tools come before
+// wlr-randr, and xrandr is last since under XWayland it only sees a virtual
+// screen.
+func sessionTools(getenv func(string) string) []tool {
+	var tools []tool
+	if getenv("SWAYSOCK") != "" {
+		tools = append(tools, swaymsg)
 	}
-
-	// If no primary found, mark the first display as main
-	if !primaryFound && len(displays) > 0 {
-		displays[0].Main = true
+	if getenv("HYPRLAND_INSTANCE_SIGNATURE") != "" {
+		tools = append(tools, hyprctl)
 	}
-
-	if len(displays) == 0 {
-		return nil, errors.New("no displays found via xrandr")
+	if getenv("WAYLAND_DISPLAY") != "" || getenv("XDG_SESSION_TYPE") == "wayland" {
+		tools = append(tools, wlrRandr)
 	}
-
-	return displays, nil
+	return append(tools, xrandr)
 }
 
-// MainWidth returns the width of the primary display.
-func MainWidth() (int64, error) {
-	displays, err := Enumerate()
-	if err != nil {
-		return 0, err
+// findTool returns the first session tool installed in PATH.
+func findTool() (tool, bool) {
+	for _, t := range sessionTools(os.Getenv) {
+		if _, err := exec.LookPath(t.name); err == nil {
+			return t, true
+		}
 	}
+	return tool{}, false
+}
 
-	for _, d := range displays {
-		if d.Main {
-			return d.Width, nil
+// platformProvider detects displays with the session's display tool.
+type platformProvider struct{}
+
+// Enumerate returns information about all active displays using the display
+// tool for the current session: swaymsg, hyprctl, wlr-randr or xrandr.
+func (platformProvider) Enumerate() ([]Info, error) {
+	t, ok := findTool()
+	if !ok {
+		var names []string
+		for _, t := range sessionTools(os.Getenv) {
+			names = append(names, t.name)
 		}
+		return nil, fmt.Errorf("no display tool found (install one of: %s)", strings.Join(names, ", "))
+	}
+
+	output, err := exec.Command(t.name, t.args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w", t.name, err)
 	}
 
-	return 0, errors.New("no primary display found")
+	return t.parse(output)
 }
 
-// Available returns true since xrandr-based detection is available on Linux.
-func Available() bool {
-	_, err := exec.LookPath("xrandr")
-	return err == nil
+// Available returns true if a display tool for the session is installed.
+func (platformProvider) Available() bool {
+	_, ok := findTool()
+	return ok
 }
@@ -3,12 +3,13 @@
 package display
 
 import (
+	"strings"
 	"testing"
 )
 
 func TestEnumerate(t *testing.T) {
 	if !Available() {
-		t.Skip("xrandr not available")
+		t.Skip("no display tool available")
 	}
 
 	displays, err := Enumerate()
@@ -38,19 +39,27 @@ func TestEnumerate(t *testing.T) {
 	}
 }
 
-func TestMainWidth(t *testing.T) {
-	if !Available() {
-		t.Skip("xrandr not available")
+func TestSessionTools(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want []string
+	}{
+		{"x11", map[string]string{"XDG_SESSION_TYPE": "x11"}, []string{"xrandr"}},
+		{"sway", map[string]string{"SWAYSOCK": "/run/sway.sock", "WAYLAND_DISPLAY": "wayland-1"}, []string{"swaymsg", "wlr-randr", "xrandr"}},
+		{"hyprland", map[string]string{"HYPRLAND_INSTANCE_SIGNATURE": "abc", "XDG_SESSION_TYPE": "wayland"}, []string{"hyprctl", "wlr-randr", "xrandr"}},
+		{"other wayland", map[string]string{"WAYLAND_DISPLAY": "wayland-0"}, []string{"wlr-randr", "xrandr"}},
 	}
 
-	width, err := MainWidth()
-	if err != nil {
-		t.Fatalf("MainWidth() error: %v", err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, tool := range sessionTools(func(key string) string { return tt.env[key] }) {
+				got = append(got, tool.name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("sessionTools() = %v; want %v", got, tt.want)
+			}
+		})
 	}
-
-	if width <= 0 {
-		t.Errorf("MainWidth() = %d; want > 0", width)
-	}
-
-	t.Logf("Main display width: %d", width)
 }
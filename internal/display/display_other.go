@@ -5,22 +5,17 @@ package display
 import "errors"
 
 // ErrUnsupportedPlatform indicates display detection is not available.
-var ErrUnsupportedPlatform = errors.New("display detection is only available on macOS")
+var ErrUnsupportedPlatform = errors.New("display detection is only available on macOS and Linux")
 
-// Enumerate returns an error on non-macOS platforms.
-func Enumerate() ([]Info, error) {
-	return nil, ErrUnsupportedPlatform
-}
+// platformProvider reports that detection is unsupported on this platform.
+type platformProvider struct{}
 
-// MainWidth returns an error on non-macOS platforms.
-func MainWidth() (int64, error) {
-	return 0, ErrUnsupportedPlatform
+// Enumerate returns an error on unsupported platforms.
+func (platformProvider) Enumerate() ([]Info, error) {
+	return nil, ErrUnsupportedPlatform
 }
 
-// available indicates whether display detection is available on this platform.
-const available = false
-
-// Available returns true if display detection is supported on this platform.
-func Available() bool {
-	return available
+// Available returns false on unsupported platforms.
+func (platformProvider) Available() bool {
+	return false
 }
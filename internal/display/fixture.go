@@ -0,0 +1,89 @@
+package display
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// FixtureProvider reads displays from a JSON or TOML file, for testing and
+// for machines where detection is unavailable. The format is chosen by file
+// extension (.toml, otherwise JSON):
+//
+//	[[displays]]
+//	name = "Built-in Retina Display"
+//	width = 1512
+//	height = 982
+//	width_mm = 302 # optional, needed for sizes in cm
+//	main = true
+type FixtureProvider struct {
+	Path string
+}
+
+// fixtureFile is the structure of a display fixture file.
+type fixtureFile struct {
+	Displays []struct {
+		Name    string `json:"name" toml:"name"`
+		Width   int64  `json:"width" toml:"width"`
+		Height  int64  `json:"height" toml:"height"`
+		WidthMM int64  `json:"width_mm" toml:"width_mm"`
+		Main    bool   `json:"main" toml:"main"`
+	} `json:"displays" toml:"displays"`
+}
+
+// Available returns true; errors reading the file surface from Enumerate.
+func (p FixtureProvider) Available() bool {
+	return true
+}
+
+// Enumerate returns the displays listed in the fixture file. If none is
+// marked main, the first display is.
+func (p FixtureProvider) Enumerate() ([]Info, error) {
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return nil, fmt.Errorf("read display fixture: %w", err)
+	}
+
+	var file fixtureFile
+	if strings.EqualFold(filepath.Ext(p.Path), ".toml") {
+		err = toml.Unmarshal(data, &file)
+	} else {
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse display fixture %s: %w", p.Path, err)
+	}
+
+	if len(file.Displays) == 0 {
+		return nil, fmt.Errorf("no displays found in %s", p.Path)
+	}
+
+	displays := make([]Info, 0, len(file.Displays))
+	var mainFound bool
+	for i, d := range file.Displays {
+		if d.Name == "" || d.Width <= 0 {
+			return nil, fmt.Errorf("display %d in %s: %w", i+1, p.Path, errInvalidFixture)
+		}
+		mainFound = mainFound || d.Main
+		displays = append(displays, Info{
+			ID:      uint32(i),
+			Name:    d.Name,
+			Width:   d.Width,
+			Height:  d.Height,
+			WidthMM: d.WidthMM,
+			Main:    d.Main,
+		})
+	}
+	if !mainFound {
+		displays[0].Main = true
+	}
+
+	return displays, nil
+}
+
+var errInvalidFixture = errors.New("name and a positive width are required")
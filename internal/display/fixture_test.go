@@ -0,0 +1,104 @@
+package display
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFixtureProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []Info
+	}{
+		{
+			name: "json",
+			file: "displays.json",
+			content: `{"displays": [
+				{"name": "Built-in Retina Display", "width": 1512, "height": 982},
+				{"name": "DELL U2722D", "width": 2560, "height": 1440, "main": true}
+			]}`,
+			want: []Info{
+				{ID: 0, Name: "Built-in Retina Display", Width: 1512, Height: 982},
+				{ID: 1, Name: "DELL U2722D", Width: 2560, Height: 1440, Main: true},
+			},
+		},
+		{
+			name: "toml without main marks the first display",
+			file: "displays.toml",
+			content: `[[displays]]
+name = "Built-in Retina Display"
+width = 1512
+height = 982
+
+[[displays]]
+name = "DELL U2722D"
+width = 2560
+`,
+			want: []Info{
+				{ID: 0, Name: "Built-in Retina Display", Width: 1512, Height: 982, Main: true},
+				{ID: 1, Name: "DELL U2722D", Width: 2560},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := FixtureProvider{Path: path}.Enumerate()
+			if err != nil {
+				t.Fatalf("Enumerate() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Enumerate() = %+v; want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFixtureProviderErrors(t *testing.T) {
+	tests := map[string]string{
+		"empty.json":    `{"displays": []}`,
+		"invalid.json":  `{`,
+		"no-width.json": `{"displays": [{"name": "main"}]}`,
+		"invalid.toml":  `[[displays]`,
+	}
+
+	dir := t.TempDir()
+	for file, content := range tests {
+		t.Run(file, func(t *testing.T) {
+			path := filepath.Join(dir, file)
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := (FixtureProvider{Path: path}).Enumerate(); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+
+	if _, err := (FixtureProvider{Path: filepath.Join(dir, "missing.json")}).Enumerate(); err == nil {
+		t.Error("expected error for missing file, got nil")
+	}
+}
+
+func TestCurrentProvider(t *testing.T) {
+	t.Setenv(EnvFixture, "displays.toml")
+	if p, ok := CurrentProvider().(FixtureProvider); !ok || p.Path != "displays.toml" {
+		t.Errorf("CurrentProvider() = %#v; want FixtureProvider for %s", CurrentProvider(), EnvFixture)
+	}
+
+	custom := FixtureProvider{Path: "custom.json"}
+	SetProvider(custom)
+	defer SetProvider(nil)
+	if CurrentProvider() != Provider(custom) {
+		t.Errorf("CurrentProvider() = %#v; want the provider passed to SetProvider", CurrentProvider())
+	}
+}
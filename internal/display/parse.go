@@ -0,0 +1,277 @@
+package display
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The parsers below turn the output of Linux display tools into Info values.
+// They are platform-independent so the fixture tests run everywhere.
+//
+// Wayland compositors lay out windows in logical pixels, so widths are the
+// mode size divided by the output scale (and rotated when transformed),
+// matching the point-based widths CoreGraphics reports on macOS.
+
+// xrandr output patterns: "DP-1 connected primary 2560x1440+0+0 left ... 597mm x 336mm"
+var (
+	connectedPattern   = regexp.MustCompile(`^(\S+)\s+connected\s+(primary\s+)?(\d+)x(\d+)`)
+	xrandrRotated      = regexp.MustCompile(`\d+x\d+[+-]\d+[+-]\d+\s+(?:left|right)\s`)
+	xrandrPhysicalSize = regexp.MustCompile(`\s(\d+)mm x (\d+)mm`)
+)
+
+// parseXrandr parses `xrandr --query` output.
+func parseXrandr(output []byte) ([]Info, error) {
+	var displays []Info
+	var primaryFound bool
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		matches := connectedPattern.FindStringSubmatch(scanner.Text())
+		if matches == nil {
+			continue
+		}
+
+		isPrimary := strings.TrimSpace(matches[2]) == "primary"
+		width, _ := strconv.ParseInt(matches[3], 10, 64)
+		height, _ := strconv.ParseInt(matches[4], 10, 64)
+
+		// The mode size is reported as rotated but the physical size is the
+		// panel's, so a display turned 90 or 270 degrees is as wide as the
+		// panel is tall.
+		var widthMM int64
+		if m := xrandrPhysicalSize.FindStringSubmatch(scanner.Text()); m != nil {
+			mm := m[1]
+			if xrandrRotated.MatchString(scanner.Text()) {
+				mm = m[2]
+			}
+			widthMM, _ = strconv.ParseInt(mm, 10, 64)
+		}
+
+		if isPrimary {
+			primaryFound = true
+		}
+
+		displays = append(displays, Info{
+			ID:      uint32(len(displays)),
+			Name:    matches[1],
+			Width:   width,
+			Height:  height,
+			WidthMM: widthMM,
+			Main:    isPrimary,
+		})
+	}
+
+	// If no primary found, mark the first display as main
+	if !primaryFound && len(displays) > 0 {
+		displays[0].Main = true
+	}
+
+	if len(displays) == 0 {
+		return nil, errors.New("no displays found via xrandr")
+	}
+
+	return displays, nil
+}
+
+// swayOutput is the subset of `swaymsg -t get_outputs` used here.
+type swayOutput struct {
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+	Rect   struct {
+		X      int64 `json:"x"`
+		Y      int64 `json:"y"`
+		Width  int64 `json:"width"`
+		Height int64 `json:"height"`
+	} `json:"rect"`
+}
+
+// parseSwayOutputs parses `swaymsg -t get_outputs --raw` JSON. The rect is
+// already in logical pixels.
+func parseSwayOutputs(output []byte) ([]Info, error) {
+	var outputs []swayOutput
+	if err := json.Unmarshal(output, &outputs); err != nil {
+		return nil, fmt.Errorf("parse swaymsg output: %w", err)
+	}
+
+	var displays []waylandOutput
+	for _, o := range outputs {
+		if !o.Active {
+			continue
+		}
+		displays = append(displays, waylandOutput{name: o.Name, width: o.Rect.Width, height: o.Rect.Height, x: o.Rect.X, y: o.Rect.Y})
+	}
+	return waylandDisplays(displays, "swaymsg")
+}
+
+// hyprlandMonitor is the subset of `hyprctl monitors -j` used here.
+type hyprlandMonitor struct {
+	Name      string  `json:"name"`
+	Width     int64   `json:"width"`
+	Height    int64   `json:"height"`
+	X         int64   `json:"x"`
+	Y         int64   `json:"y"`
+	Scale     float64 `json:"scale"`
+	Transform int     `json:"transform"`
+	Disabled  bool    `json:"disabled"`
+}
+
+// parseHyprlandMonitors parses `hyprctl monitors -j` JSON.
+func parseHyprlandMonitors(output []byte) ([]Info, error) {
+	var monitors []hyprlandMonitor
+	if err := json.Unmarshal(output, &monitors); err != nil {
+		return nil, fmt.Errorf("parse hyprctl output: %w", err)
+	}
+
+	var displays []waylandOutput
+	for _, m := range monitors {
+		if m.Disabled {
+			continue
+		}
+		width, height := m.Width, m.Height
+		// Odd transforms (90, 270 and their flipped variants) rotate the output.
+		if m.Transform%2 == 1 {
+			width, height = height, width
+		}
+		displays = append(displays, waylandOutput{
+			name:   m.Name,
+			width:  logicalSize(width, m.Scale),
+			height: logicalSize(height, m.Scale),
+			x:      m.X,
+			y:      m.Y,
+		})
+	}
+	return waylandDisplays(displays, "hyprctl")
+}
+
+// wlr-randr patterns: "  2560x1440 px, 59.951000 Hz (preferred, current)",
+// "  Position: 0,0", "  Scale: 1.500000", "  Transform: 90", "  Enabled: no".
+var (
+	wlrModePattern      = regexp.MustCompile(`^\s+(\d+)x(\d+) px.*\bcurrent\b`)
+	wlrPositionPattern  = regexp.MustCompile(`^\s+Position: (-?\d+),(-?\d+)`)
+	wlrScalePattern     = regexp.MustCompile(`^\s+Scale: ([\d.]+)`)
+	wlrTransformPattern = regexp.MustCompile(`^\s+Transform: (\S+)`)
+	wlrEnabledPattern   = regexp.MustCompile(`^\s+Enabled: (\S+)`)
+	wlrPhysicalPattern  = regexp.MustCompile(`^\s+Physical size: (\d+)x(\d+) mm`)
+)
+
+// parseWlrRandr parses the human-readable output of `wlr-randr`.
+func parseWlrRandr(output []byte) ([]Info, error) {
+	type wlrOutput struct {
+		name          string
+		width, height int64
+		mmW, mmH      int64
+		x, y          int64
+		scale         float64
+		rotated       bool
+		disabled      bool
+	}
+
+	var outputs []*wlrOutput
+	var cur *wlrOutput
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		// Unindented lines start a new output: `DP-1 "Dell Inc. ..."`.
+		if line[0] != ' ' && line[0] != '\t' {
+			cur = &wlrOutput{name: strings.Fields(line)[0], scale: 1}
+			outputs = append(outputs, cur)
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+
+		if m := wlrModePattern.FindStringSubmatch(line); m != nil {
+			cur.width, _ = strconv.ParseInt(m[1], 10, 64)
+			cur.height, _ = strconv.ParseInt(m[2], 10, 64)
+		} else if m := wlrPositionPattern.FindStringSubmatch(line); m != nil {
+			cur.x, _ = strconv.ParseInt(m[1], 10, 64)
+			cur.y, _ = strconv.ParseInt(m[2], 10, 64)
+		} else if m := wlrScalePattern.FindStringSubmatch(line); m != nil {
+			cur.scale, _ = strconv.ParseFloat(m[1], 64)
+		} else if m := wlrTransformPattern.FindStringSubmatch(line); m != nil {
+			cur.rotated = strings.HasSuffix(m[1], "90") || strings.HasSuffix(m[1], "270")
+		} else if m := wlrPhysicalPattern.FindStringSubmatch(line); m != nil {
+			cur.mmW, _ = strconv.ParseInt(m[1], 10, 64)
+			cur.mmH, _ = strconv.ParseInt(m[2], 10, 64)
+		} else if m := wlrEnabledPattern.FindStringSubmatch(line); m != nil {
+			cur.disabled = m[1] == "no"
+		}
+	}
+
+	var displays []waylandOutput
+	for _, o := range outputs {
+		// Disabled outputs have no current mode.
+		if o.disabled || o.width == 0 {
+			continue
+		}
+		width, height, widthMM := o.width, o.height, o.mmW
+		if o.rotated {
+			width, height, widthMM = height, width, o.mmH
+		}
+		displays = append(displays, waylandOutput{
+			name:    o.name,
+			width:   logicalSize(width, o.scale),
+			height:  logicalSize(height, o.scale),
+			widthMM: widthMM,
+			x:       o.x,
+			y:       o.y,
+		})
+	}
+	return waylandDisplays(displays, "wlr-randr")
+}
+
+// waylandOutput is an active output in compositor layout coordinates.
+type waylandOutput struct {
+	name          string
+	width, height int64
+	widthMM       int64 // 0 when the tool does not report it
+	x, y          int64
+}
+
+// waylandDisplays converts outputs to Info values. Wayland has no primary
+// output, so the one at the layout origin is treated as main, falling back to
+// the first output.
+func waylandDisplays(outputs []waylandOutput, tool string) ([]Info, error) {
+	if len(outputs) == 0 {
+		return nil, fmt.Errorf("no displays found via %s", tool)
+	}
+
+	displays := make([]Info, 0, len(outputs))
+	mainIdx := -1
+	for i, o := range outputs {
+		if mainIdx < 0 && o.x == 0 && o.y == 0 {
+			mainIdx = i
+		}
+		displays = append(displays, Info{
+			ID:      uint32(i),
+			Name:    o.name,
+			Width:   o.width,
+			Height:  o.height,
+			WidthMM: o.widthMM,
+		})
+	}
+	displays[max(mainIdx, 0)].Main = true
+
+	return displays, nil
+}
+
+// logicalSize scales a mode dimension to logical pixels.
+func logicalSize(size int64, scale float64) int64 {
+	if scale <= 0 {
+		return size
+	}
+	return int64(math.Round(float64(size) / scale))
+}
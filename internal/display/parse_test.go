@@ -0,0 +1,103 @@
+package display
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParsers(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		parse   func([]byte) ([]Info, error)
+		want    []Info
+	}{
+		{
+			name:    "xrandr primary output",
+			fixture: "xrandr.txt",
+			parse:   parseXrandr,
+			want: []Info{
+				{ID: 0, Name: "eDP-1", Width: 1920, Height: 1080, WidthMM: 309},
+				{ID: 1, Name: "DP-1", Width: 2560, Height: 1440, WidthMM: 597, Main: true},
+			},
+		},
+		{
+			name:    "xrandr rotated outputs",
+			fixture: "xrandr-rotated.txt",
+			parse:   parseXrandr,
+			want: []Info{
+				{ID: 0, Name: "DP-1", Width: 2560, Height: 1440, WidthMM: 597, Main: true},
+				{ID: 1, Name: "DP-2", Width: 1440, Height: 2560, WidthMM: 336},
+				{ID: 2, Name: "HDMI-1", Width: 1080, Height: 1920, WidthMM: 296},
+			},
+		},
+		{
+			name:    "sway logical rects, inactive skipped",
+			fixture: "sway-outputs.json",
+			parse:   parseSwayOutputs,
+			want: []Info{
+				{ID: 0, Name: "eDP-1", Width: 1280, Height: 800},
+				{ID: 1, Name: "DP-1", Width: 2560, Height: 1440, Main: true},
+			},
+		},
+		{
+			name:    "hyprland scaled and rotated",
+			fixture: "hyprctl-monitors.json",
+			parse:   parseHyprlandMonitors,
+			want: []Info{
+				{ID: 0, Name: "eDP-1", Width: 1920, Height: 1200, Main: true},
+				{ID: 1, Name: "DP-2", Width: 1080, Height: 1920},
+			},
+		},
+		{
+			name:    "wlr-randr scaled and rotated, disabled skipped",
+			fixture: "wlr-randr.txt",
+			parse:   parseWlrRandr,
+			want: []Info{
+				{ID: 0, Name: "DP-1", Width: 2560, Height: 1440, WidthMM: 600},
+				{ID: 1, Name: "eDP-1", Width: 1280, Height: 2048, WidthMM: 180, Main: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.fixture))
+			if err != nil {
+				t.Fatalf("read fixture: %v", err)
+			}
+
+			got, err := tt.parse(data)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parse() = %+v; want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsersNoDisplays(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		parse func([]byte) ([]Info, error)
+	}{
+		{"xrandr", "Screen 0: minimum 320 x 200\nHDMI-1 disconnected\n", parseXrandr},
+		{"sway", `[{"name": "DP-1", "active": false}]`, parseSwayOutputs},
+		{"sway invalid json", `{`, parseSwayOutputs},
+		{"hyprland", `[]`, parseHyprlandMonitors},
+		{"wlr-randr", "DP-1 \"Dell\"\n  Enabled: no\n", parseWlrRandr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.parse([]byte(tt.input)); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
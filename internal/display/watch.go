@@ -0,0 +1,108 @@
+package display
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+)
+
+// Watcher polls for display changes. A change is only reported once the
+// displays have stayed the same for the debounce period, so a monitor that
+// flaps while a laptop is docked triggers a single update.
+type Watcher struct {
+	Provider Provider      // nil uses CurrentProvider
+	Interval time.Duration // time between polls
+	Debounce time.Duration // how long a change must persist before it is reported
+	OnError  func(error)   // called when a poll fails; nil ignores errors
+}
+
+// Watch polls until ctx is done, calling onChange with the new displays after
+// each settled change. The displays present when Watch starts are the
+// baseline and are not reported. It returns ctx.Err().
+func (w Watcher) Watch(ctx context.Context, onChange func([]Info)) error {
+	provider := w.Provider
+	if provider == nil {
+		provider = CurrentProvider()
+	}
+
+	d := debouncer{delay: w.Debounce}
+	if displays, err := provider.Enumerate(); err == nil {
+		d.current = displays
+	} else if w.OnError != nil {
+		w.OnError(err)
+	}
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			displays, err := provider.Enumerate()
+			if err != nil {
+				// Detection often fails mid-reconfiguration; keep waiting.
+				if w.OnError != nil {
+					w.OnError(err)
+				}
+				continue
+			}
+			if d.observe(displays, now) {
+				onChange(displays)
+			}
+		}
+	}
+}
+
+// debouncer tracks polled displays and decides when a change has settled.
+type debouncer struct {
+	delay   time.Duration
+	current []Info // last reported displays
+
+	pending []Info // displays that differ from current, if any
+	since   time.Time
+}
+
+// observe records the displays seen at now and reports whether they are a
+// change from the current displays that has lasted at least the delay.
+func (d *debouncer) observe(displays []Info, now time.Time) bool {
+	if sameDisplays(displays, d.current) {
+		// Flapped back before settling.
+		d.pending = nil
+		return false
+	}
+
+	if d.pending == nil || !sameDisplays(displays, d.pending) {
+		d.pending, d.since = displays, now
+	}
+	if now.Sub(d.since) < d.delay {
+		return false
+	}
+
+	d.current, d.pending = displays, nil
+	return true
+}
+
+// sameDisplays reports whether a and b describe the same displays, ignoring
+// order and platform IDs.
+func sameDisplays(a, b []Info) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	return slices.Equal(sortedDisplays(a), sortedDisplays(b))
+}
+
+// sortedDisplays returns a copy of displays without IDs, sorted by name.
+func sortedDisplays(displays []Info) []Info {
+	sorted := make([]Info, len(displays))
+	for i, d := range displays {
+		d.ID = 0
+		sorted[i] = d
+	}
+	slices.SortFunc(sorted, func(a, b Info) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return sorted
+}
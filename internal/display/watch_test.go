@@ -0,0 +1,75 @@
+package display
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDebouncer(t *testing.T) {
+	laptop := []Info{{ID: 1, Name: "Built-in Retina Display", Width: 1512, Main: true}}
+	docked := []Info{
+		{ID: 2, Name: "DELL U2722D", Width: 2560, Main: true},
+		{ID: 1, Name: "Built-in Retina Display", Width: 1512},
+	}
+	// The same displays enumerated in another order with new IDs.
+	dockedAgain := []Info{
+		{ID: 7, Name: "Built-in Retina Display", Width: 1512},
+		{ID: 8, Name: "DELL U2722D", Width: 2560, Main: true},
+	}
+
+	type poll struct {
+		at       time.Duration
+		displays []Info
+		want     bool
+	}
+	tests := []struct {
+		name  string
+		polls []poll
+	}{
+		{
+			name: "unchanged",
+			polls: []poll{
+				{at: 0, displays: laptop},
+				{at: 5 * time.Second, displays: laptop},
+			},
+		},
+		{
+			name: "change settles after the delay",
+			polls: []poll{
+				{at: 0, displays: docked},
+				{at: 2 * time.Second, displays: dockedAgain},
+				{at: 3 * time.Second, displays: docked, want: true},
+				{at: 4 * time.Second, displays: docked},
+			},
+		},
+		{
+			name: "flap back is ignored",
+			polls: []poll{
+				{at: 0, displays: docked},
+				{at: 1 * time.Second, displays: laptop},
+				{at: 5 * time.Second, displays: laptop},
+			},
+		},
+		{
+			name: "a different change restarts the delay",
+			polls: []poll{
+				{at: 0, displays: docked},
+				{at: 2 * time.Second, displays: docked[:1]},
+				{at: 3 * time.Second, displays: docked[:1]},
+				{at: 5 * time.Second, displays: docked[:1], want: true},
+			},
+		},
+	}
+
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := debouncer{delay: 3 * time.Second, current: laptop}
+			for _, p := range tt.polls {
+				if got := d.observe(p.displays, start.Add(p.at)); got != p.want {
+					t.Errorf("observe at %v = %v; want %v", p.at, got, p.want)
+				}
+			}
+		})
+	}
+}
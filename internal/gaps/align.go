@@ -0,0 +1,58 @@
+package gaps
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Alignment is the edge of the monitor a workspace is pinned to.
+type Alignment string
+
+const (
+	AlignCenter Alignment = "center"
+	AlignLeft   Alignment = "left"
+	AlignRight  Alignment = "right"
+)
+
+// ErrInvalidMargin indicates a margin that does not leave room for the
+// workspace on the monitor.
+var ErrInvalidMargin = errors.New("margin would push the workspace off the monitor")
+
+// ParseAlignment validates an alignment name. An empty name is AlignCenter.
+func ParseAlignment(s string) (Alignment, error) {
+	switch a := Alignment(strings.ToLower(s)); a {
+	case "":
+		return AlignCenter, nil
+	case AlignCenter, AlignLeft, AlignRight:
+		return a, nil
+	}
+	return "", fmt.Errorf("unknown alignment %q (expected left, center or right)", s)
+}
+
+// CalculateAlignedGaps computes the gaps that place a workspace of
+// workspaceWidth pixels flush against an edge, marginPixels away from it.
+// A centered workspace ignores the margin.
+func CalculateAlignedGaps(monitorWidth, workspaceWidth, marginPixels int64, align Alignment, rounding Rounding) (ShiftedGaps, error) {
+	if align == AlignCenter || align == "" {
+		return shiftGaps(monitorWidth, centeredGap(monitorWidth, workspaceWidth, rounding), 0, rounding), nil
+	}
+
+	spare := monitorWidth - workspaceWidth
+	if marginPixels < 0 || marginPixels > spare {
+		return ShiftedGaps{}, ErrInvalidMargin
+	}
+
+	left, right := marginPixels, spare-marginPixels
+	if align == AlignRight {
+		left, right = right, left
+	}
+	return ShiftedGaps{
+		LeftGapPercent:  int64(math.Round(float64(left) * 100.0 / float64(monitorWidth))),
+		RightGapPercent: int64(math.Round(float64(right) * 100.0 / float64(monitorWidth))),
+		LeftGapPixels:   left,
+		RightGapPixels:  right,
+		WorkspacePixels: workspaceWidth,
+	}, nil
+}
@@ -10,43 +10,50 @@ import (
 var ErrInvalidPercentage = errors.New("percentage must be between 1 and 100")
 
 // ValidatePercentage ensures the percentage is within the valid range (1-100).
-func ValidatePercentage(percentage int64) error {
-	if percentage < 1 || percentage > 100 {
+func ValidatePercentage(percentage float64) error {
+	if !(percentage >= 1 && percentage <= 100) {
 		return ErrInvalidPercentage
 	}
 	return nil
 }
 
+// percentagePrecision is the number of decimal places kept in percentages.
+const percentagePrecision = 2
+
+// RoundPercentage rounds a percentage to two decimal places, so repeated
+// adjustments like 0.1 do not accumulate floating point noise.
+func RoundPercentage(percentage float64) float64 {
+	scale := math.Pow10(percentagePrecision)
+	return math.Round(percentage*scale) / scale
+}
+
 // CalculateGapSize computes the gap size in pixels from monitor width and percentage.
 // Formula: gap = monitor_width * ((100 - percentage) / 100) / 2
 // This gives the gap on each side (left and right) to achieve the desired workspace percentage.
-func CalculateGapSize(monitorWidth, percentage int64) int64 {
-	fraction := float64(100-percentage) / 100.0
+func CalculateGapSize(monitorWidth int64, percentage float64, rounding Rounding) int64 {
+	fraction := (100 - percentage) / 100.0
 	gap := float64(monitorWidth) * fraction / 2.0
-	return int64(math.Round(gap))
+	return rounding.pixels(gap)
 }
 
 // ErrInvalidShift indicates a shift that would result in negative gaps.
 var ErrInvalidShift = errors.New("shift would result in negative gaps")
 
-// ShiftedGaps holds the calculated left and right gap sizes.
+// ShiftedGaps holds the calculated left and right gap sizes. The gaps and the
+// workspace always add up to the monitor width.
 type ShiftedGaps struct {
 	LeftGapPercent  int64
 	RightGapPercent int64
 	LeftGapPixels   int64
 	RightGapPixels  int64
+	WorkspacePixels int64
 }
 
 // ValidateShift checks if a shift is valid for the given workspace percentage.
 // The shift is expressed as a percentage of monitor width and cannot exceed the
 // centered per-side gap (in pixels), otherwise one side would go negative.
-func ValidateShift(monitorWidth, percentage, shiftPercent int64) error {
-	baseGapPixels := CalculateGapSize(monitorWidth, percentage)
-	shiftPixels := int64(math.Round(float64(monitorWidth) * math.Abs(float64(shiftPercent)) / 100.0))
-	if shiftPixels > baseGapPixels {
-		return ErrInvalidShift
-	}
-	return nil
+func ValidateShift(monitorWidth int64, percentage float64, shiftPercent int64, rounding Rounding) error {
+	return validateShift(monitorWidth, CalculateGapSize(monitorWidth, percentage, rounding), shiftPercent, rounding)
 }
 
 // CalculateShiftedGaps computes the left and right gaps with a shift applied.
@@ -56,12 +63,50 @@ func ValidateShift(monitorWidth, percentage, shiftPercent int64) error {
 //
 // The calculation is done in pixels so the workspace width remains constant (in pixels)
 // for a given percentage.
-func CalculateShiftedGaps(monitorWidth, percentage, shiftPercent int64) ShiftedGaps {
-	baseGapPixels := CalculateGapSize(monitorWidth, percentage)
-	shiftPixels := int64(math.Round(float64(monitorWidth) * float64(shiftPercent) / 100.0))
+func CalculateShiftedGaps(monitorWidth int64, percentage float64, shiftPercent int64, rounding Rounding) ShiftedGaps {
+	return shiftGaps(monitorWidth, CalculateGapSize(monitorWidth, percentage, rounding), shiftPercent, rounding)
+}
 
-	leftGapPixels := baseGapPixels + shiftPixels
-	rightGapPixels := baseGapPixels - shiftPixels
+// CalculateShiftedGapsForWidth is CalculateShiftedGaps for a workspace given
+// as an absolute width in pixels.
+func CalculateShiftedGapsForWidth(monitorWidth, workspaceWidth, shiftPercent int64, rounding Rounding) ShiftedGaps {
+	return shiftGaps(monitorWidth, centeredGap(monitorWidth, workspaceWidth, rounding), shiftPercent, rounding)
+}
+
+// ValidateShiftForWidth is ValidateShift for a workspace given as an absolute
+// width in pixels.
+func ValidateShiftForWidth(monitorWidth, workspaceWidth, shiftPercent int64, rounding Rounding) error {
+	return validateShift(monitorWidth, centeredGap(monitorWidth, workspaceWidth, rounding), shiftPercent, rounding)
+}
+
+func validateShift(monitorWidth, baseGapPixels, shiftPercent int64, rounding Rounding) error {
+	if abs(shiftPixels(monitorWidth, shiftPercent, rounding)) > baseGapPixels {
+		return ErrInvalidShift
+	}
+	return nil
+}
+
+// centeredGap is the per-side gap that centers a workspace of workspaceWidth pixels.
+func centeredGap(monitorWidth, workspaceWidth int64, rounding Rounding) int64 {
+	return rounding.pixels(float64(monitorWidth-workspaceWidth) / 2.0)
+}
+
+// shiftPixels converts a shift percentage to pixels, rounding its magnitude
+// so left and right shifts of the same size move the same distance.
+func shiftPixels(monitorWidth, shiftPercent int64, rounding Rounding) int64 {
+	pixels := rounding.pixels(float64(monitorWidth) * float64(abs(shiftPercent)) / 100.0)
+	if shiftPercent < 0 {
+		return -pixels
+	}
+	return pixels
+}
+
+// shiftGaps moves a centered per-side gap by shiftPercent of the monitor width.
+func shiftGaps(monitorWidth, baseGapPixels, shiftPercent int64, rounding Rounding) ShiftedGaps {
+	shift := shiftPixels(monitorWidth, shiftPercent, rounding)
+
+	leftGapPixels := baseGapPixels + shift
+	rightGapPixels := baseGapPixels - shift
 
 	leftPercent := int64(math.Round(float64(leftGapPixels) * 100.0 / float64(monitorWidth)))
 	rightPercent := int64(math.Round(float64(rightGapPixels) * 100.0 / float64(monitorWidth)))
@@ -71,5 +116,26 @@ func CalculateShiftedGaps(monitorWidth, percentage, shiftPercent int64) ShiftedG
 		RightGapPercent: rightPercent,
 		LeftGapPixels:   leftGapPixels,
 		RightGapPixels:  rightGapPixels,
+		WorkspacePixels: monitorWidth - leftGapPixels - rightGapPixels,
+	}
+}
+
+// VerticalGaps holds the top and bottom gap sizes for a vertical percentage.
+type VerticalGaps struct {
+	TopGapPixels    int64
+	BottomGapPixels int64
+}
+
+// CalculateVerticalGaps computes equal top and bottom gaps that center a
+// workspace taking percentage of the monitor height.
+func CalculateVerticalGaps(monitorHeight int64, percentage float64, rounding Rounding) VerticalGaps {
+	gap := CalculateGapSize(monitorHeight, percentage, rounding)
+	return VerticalGaps{TopGapPixels: gap, BottomGapPixels: gap}
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
 	}
+	return v
 }
@@ -1,20 +1,24 @@
 package gaps
 
 import (
+	"errors"
 	"testing"
 )
 
 func TestValidatePercentage(t *testing.T) {
 	tests := []struct {
 		name       string
-		percentage int64
+		percentage float64
 		wantErr    bool
 	}{
 		{"zero is invalid", 0, true},
 		{"negative is invalid", -10, true},
 		{"101 is invalid", 101, true},
+		{"0.5 is invalid", 0.5, true},
+		{"100.5 is invalid", 100.5, true},
 		{"1 is valid", 1, false},
 		{"50 is valid", 50, false},
+		{"62.5 is valid", 62.5, false},
 		{"100 is valid", 100, false},
 	}
 
@@ -22,7 +26,7 @@ func TestValidatePercentage(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			err := ValidatePercentage(tt.percentage)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("ValidatePercentage(%d) error = %v, wantErr %v", tt.percentage, err, tt.wantErr)
+				t.Errorf("ValidatePercentage(%g) error = %v, wantErr %v", tt.percentage, err, tt.wantErr)
 			}
 		})
 	}
@@ -32,21 +36,181 @@ func TestCalculateGapSize(t *testing.T) {
 	tests := []struct {
 		name       string
 		width      int64
-		percentage int64
+		percentage float64
 		want       int64
 	}{
 		{"40% of 1000px", 1000, 40, 300}, // 1000 * 0.60 / 2 = 300
 		{"50% of 1920px", 1920, 50, 480}, // 1920 * 0.50 / 2 = 480
 		{"80% of 2560px", 2560, 80, 256}, // 2560 * 0.20 / 2 = 256
 		{"100% means no gap", 2560, 100, 0},
-		{"1% is almost full gap", 1000, 1, 495}, // 1000 * 0.99 / 2 = 495
+		{"1% is almost full gap", 1000, 1, 495},          // 1000 * 0.99 / 2 = 495
+		{"62.5% of 5120px", 5120, 62.5, 960},             // 5120 * 0.375 / 2 = 960
+		{"62.3% of 5120px rounds up", 5120, 62.3, 965},   // 965.12
+		{"62.7% of 5120px rounds down", 5120, 62.7, 955}, // 954.88
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := CalculateGapSize(tt.width, tt.percentage)
+			got := CalculateGapSize(tt.width, tt.percentage, RoundNearest)
 			if got != tt.want {
-				t.Errorf("CalculateGapSize(%d, %d) = %d; want %d", tt.width, tt.percentage, got, tt.want)
+				t.Errorf("CalculateGapSize(%d, %g) = %d; want %d", tt.width, tt.percentage, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateVerticalGaps(t *testing.T) {
+	tests := []struct {
+		name       string
+		height     int64
+		percentage float64
+		want       int64
+	}{
+		{"80% of 1440px", 1440, 80, 144},
+		{"100% means no gap", 1440, 100, 0},
+		{"portrait 90% of 2560px", 2560, 90, 128},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateVerticalGaps(tt.height, tt.percentage, RoundNearest)
+			if got.TopGapPixels != tt.want || got.BottomGapPixels != tt.want {
+				t.Errorf("CalculateVerticalGaps(%d, %g) = %+v; want %d top and bottom", tt.height, tt.percentage, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundPercentage(t *testing.T) {
+	got := 60.0
+	for range 3 {
+		got = RoundPercentage(got + 0.1)
+	}
+	if got != 60.3 {
+		t.Errorf("RoundPercentage after three 0.1 steps = %v; want 60.3", got)
+	}
+	if got := RoundPercentage(33.3333); got != 33.33 {
+		t.Errorf("RoundPercentage(33.3333) = %v; want 33.33", got)
+	}
+}
+
+func TestCalculateShiftedGapsRounding(t *testing.T) {
+	tests := []struct {
+		name       string
+		width      int64
+		percentage float64
+		shift      int64
+		rounding   Rounding
+		wantLeft   int64
+		wantRight  int64
+	}{
+		{"nearest", 1001, 60, 0, RoundNearest, 200, 200}, // 200.2
+		{"floor", 1001, 60.1, 0, RoundFloor, 199, 199},   // 199.7
+		{"nearest rounds up", 1001, 60.1, 0, RoundNearest, 200, 200},
+		{"even", 1000, 60.52, 0, RoundEven, 198, 198}, // 197.4
+		{"even shift", 1000, 60, 1, RoundEven, 210, 190},
+		{"floor shift left", 1001, 60, -1, RoundFloor, 190, 210},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateShiftedGaps(tt.width, tt.percentage, tt.shift, tt.rounding)
+			if got.LeftGapPixels != tt.wantLeft || got.RightGapPixels != tt.wantRight {
+				t.Errorf("CalculateShiftedGaps() = %+v; want left %d, right %d", got, tt.wantLeft, tt.wantRight)
+			}
+			if sum := got.LeftGapPixels + got.WorkspacePixels + got.RightGapPixels; sum != tt.width {
+				t.Errorf("left + workspace + right = %d; want %d", sum, tt.width)
+			}
+		})
+	}
+}
+
+func TestParseRounding(t *testing.T) {
+	for input, want := range map[string]Rounding{"": RoundNearest, "nearest": RoundNearest, "FLOOR": RoundFloor, "even": RoundEven} {
+		if got, err := ParseRounding(input); err != nil || got != want {
+			t.Errorf("ParseRounding(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseRounding("ceil"); err == nil {
+		t.Error("ParseRounding(\"ceil\") succeeded; want error")
+	}
+}
+
+func TestCalculateAlignedGaps(t *testing.T) {
+	tests := []struct {
+		name      string
+		align     Alignment
+		margin    int64
+		wantLeft  int64
+		wantRight int64
+		wantErr   error
+	}{
+		{"left", AlignLeft, 0, 0, 400, nil},
+		{"left with margin", AlignLeft, 20, 20, 380, nil},
+		{"right with margin", AlignRight, 20, 380, 20, nil},
+		{"center ignores margin", AlignCenter, 20, 200, 200, nil},
+		{"margin too large", AlignLeft, 401, 0, 0, ErrInvalidMargin},
+		{"negative margin", AlignRight, -1, 0, 0, ErrInvalidMargin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CalculateAlignedGaps(1000, 600, tt.margin, tt.align, RoundNearest)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CalculateAlignedGaps() error = %v; want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.LeftGapPixels != tt.wantLeft || got.RightGapPixels != tt.wantRight {
+				t.Errorf("CalculateAlignedGaps() = %+v; want left %d, right %d", got, tt.wantLeft, tt.wantRight)
+			}
+			if sum := got.LeftGapPixels + got.WorkspacePixels + got.RightGapPixels; sum != 1000 {
+				t.Errorf("left + workspace + right = %d; want 1000", sum)
+			}
+		})
+	}
+}
+
+func TestInferLayout(t *testing.T) {
+	tests := []struct {
+		name       string
+		width      int64
+		left       int64
+		right      int64
+		rounding   Rounding
+		percentage float64
+		shift      int64
+		align      Alignment
+		margin     int64
+		exact      bool
+		wantErr    error
+	}{
+		{"centered", 1000, 200, 200, RoundNearest, 60, 0, AlignCenter, 0, true, nil},
+		{"shifted right", 1000, 250, 150, RoundNearest, 60, 5, AlignCenter, 0, true, nil},
+		{"flush left as shift", 1000, 0, 400, RoundNearest, 60, -20, AlignCenter, 0, true, nil},
+		{"fractional", 2560, 480, 480, RoundNearest, 62.5, 0, AlignCenter, 0, true, nil},
+		{"only alignment fits", 2560, 20, 1004, RoundNearest, 60, 0, AlignLeft, 20, true, nil},
+		{"odd gaps with even rounding", 1000, 201, 201, RoundEven, 59.8, 0, AlignCenter, 0, false, nil},
+		{"no room", 1000, 600, 400, RoundNearest, 0, 0, "", 0, false, ErrInvalidGaps},
+		{"negative", 1000, -10, 400, RoundNearest, 0, 0, "", 0, false, ErrInvalidGaps},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := InferLayout(tt.width, tt.left, tt.right, tt.rounding)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("InferLayout() error = %v; want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Percentage != tt.percentage || got.Shift != tt.shift || got.Align != tt.align || got.Margin != tt.margin || got.Exact != tt.exact {
+				t.Errorf("InferLayout() = %+v; want %g%% shift %d align %s margin %d exact %v",
+					got, tt.percentage, tt.shift, tt.align, tt.margin, tt.exact)
+			}
+			if tt.exact && (got.Gaps.LeftGapPixels != tt.left || got.Gaps.RightGapPixels != tt.right) {
+				t.Errorf("InferLayout() gaps = %d/%d; want %d/%d", got.Gaps.LeftGapPixels, got.Gaps.RightGapPixels, tt.left, tt.right)
 			}
 		})
 	}
@@ -0,0 +1,74 @@
+package gaps
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrInvalidGaps indicates gaps that are negative or leave no room for the
+// workspace.
+var ErrInvalidGaps = errors.New("gaps leave no room for the workspace")
+
+// InferredLayout is a layout recovered from existing gaps.
+type InferredLayout struct {
+	Percentage float64
+	Shift      int64
+	Align      Alignment // AlignCenter unless only an edge alignment fits
+	Margin     int64
+	Gaps       ShiftedGaps // the gaps the layout produces
+	Exact      bool        // whether Gaps equals the gaps it was inferred from
+}
+
+// InferLayout back-computes the layout that produces leftGap and rightGap on
+// a monitor. Percentages keep two decimals and shifts are whole percentages,
+// so not every pair of gaps can be represented: a shifted layout is tried
+// first, then one aligned to the edge with the smaller gap, and when neither
+// is exact the closest shifted layout is returned with Exact false.
+func InferLayout(monitorWidth, leftGap, rightGap int64, rounding Rounding) (InferredLayout, error) {
+	if leftGap < 0 || rightGap < 0 || leftGap+rightGap >= monitorWidth {
+		return InferredLayout{}, ErrInvalidGaps
+	}
+
+	percentage := PercentageOf(monitorWidth, monitorWidth-leftGap-rightGap)
+	shift := int64(math.Round(float64(leftGap-rightGap) / 2 * 100 / float64(monitorWidth)))
+	// Rounding can take the shift past the centered gap; back it off.
+	for shift != 0 && ValidateShift(monitorWidth, percentage, shift, rounding) != nil {
+		shift -= sign(shift)
+	}
+
+	shifted := InferredLayout{
+		Percentage: percentage,
+		Shift:      shift,
+		Align:      AlignCenter,
+		Gaps:       CalculateShiftedGaps(monitorWidth, percentage, shift, rounding),
+	}
+	shifted.Exact = shifted.Gaps.LeftGapPixels == leftGap && shifted.Gaps.RightGapPixels == rightGap
+	if shifted.Exact || leftGap == rightGap {
+		return shifted, nil
+	}
+
+	align, margin := AlignLeft, leftGap
+	if rightGap < leftGap {
+		align, margin = AlignRight, rightGap
+	}
+	workspace := monitorWidth - 2*CalculateGapSize(monitorWidth, percentage, rounding)
+	aligned, err := CalculateAlignedGaps(monitorWidth, workspace, margin, align, rounding)
+	if err == nil && aligned.LeftGapPixels == leftGap && aligned.RightGapPixels == rightGap {
+		return InferredLayout{
+			Percentage: percentage,
+			Align:      align,
+			Margin:     margin,
+			Gaps:       aligned,
+			Exact:      true,
+		}, nil
+	}
+
+	return shifted, nil
+}
+
+func sign(v int64) int64 {
+	if v < 0 {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,48 @@
+package gaps
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Rounding is the strategy used to turn fractional gap sizes into whole
+// pixels. Both sides of a centered workspace always get the same gap, and the
+// workspace takes whatever remains, so the gaps and the workspace add up to
+// the monitor width.
+type Rounding string
+
+const (
+	// RoundNearest rounds each gap to the nearest pixel. It is the default.
+	RoundNearest Rounding = "nearest"
+	// RoundFloor rounds each gap down, giving the workspace any spare pixels.
+	RoundFloor Rounding = "floor"
+	// RoundEven rounds each gap to the nearest even number of pixels, which
+	// keeps edges on whole points on 2x scaled displays.
+	RoundEven Rounding = "even"
+)
+
+// ParseRounding validates a rounding strategy name. An empty name is
+// RoundNearest.
+func ParseRounding(s string) (Rounding, error) {
+	switch r := Rounding(strings.ToLower(s)); r {
+	case "":
+		return RoundNearest, nil
+	case RoundNearest, RoundFloor, RoundEven:
+		return r, nil
+	}
+	return "", fmt.Errorf("unknown rounding %q (expected nearest, floor or even)", s)
+}
+
+// pixels rounds a non-negative pixel size with the strategy. The zero value
+// rounds to nearest.
+func (r Rounding) pixels(size float64) int64 {
+	switch r {
+	case RoundFloor:
+		return int64(math.Floor(size))
+	case RoundEven:
+		return 2 * int64(math.Round(size/2))
+	default:
+		return int64(math.Round(size))
+	}
+}
@@ -0,0 +1,97 @@
+package gaps
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Unit is the unit a workspace size is given in.
+type Unit string
+
+const (
+	UnitPercent     Unit = "%"
+	UnitPixels      Unit = "px"
+	UnitCentimeters Unit = "cm"
+)
+
+// ErrInvalidSize indicates a workspace size that cannot be parsed or does
+// not fit on the monitor.
+var ErrInvalidSize = errors.New("invalid workspace size")
+
+// ErrPhysicalSizeUnknown indicates a physical size was requested for a
+// monitor whose physical width is not known.
+var ErrPhysicalSizeUnknown = errors.New("physical monitor width unknown")
+
+// Size is a workspace width: a percentage of the monitor width, a number of
+// pixels, or a physical length.
+type Size struct {
+	Value float64
+	Unit  Unit
+}
+
+// ParseSize parses a size such as "60", "62.5%", "2560px" or "60cm". A bare
+// number is a percentage.
+func ParseSize(s string) (Size, error) {
+	text := strings.TrimSpace(strings.ToLower(s))
+
+	unit := UnitPercent
+	for _, u := range []Unit{UnitPercent, UnitPixels, UnitCentimeters} {
+		if strings.HasSuffix(text, string(u)) {
+			unit = u
+			text = strings.TrimSpace(strings.TrimSuffix(text, string(u)))
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil || value <= 0 || math.IsInf(value, 0) {
+		return Size{}, fmt.Errorf("%w %q (use a percentage, px or cm)", ErrInvalidSize, s)
+	}
+	switch unit {
+	case UnitPixels:
+		if value != math.Trunc(value) {
+			return Size{}, fmt.Errorf("%w %q (pixels must be a whole number)", ErrInvalidSize, s)
+		}
+	case UnitPercent:
+		value = RoundPercentage(value)
+	}
+
+	return Size{Value: value, Unit: unit}, nil
+}
+
+// String formats the size so that ParseSize reads it back.
+func (s Size) String() string {
+	return strconv.FormatFloat(s.Value, 'f', -1, 64) + string(s.Unit)
+}
+
+// Pixels converts the size to a workspace width in pixels on a monitor that
+// is monitorWidth pixels and monitorWidthMM millimeters wide.
+func (s Size) Pixels(monitorWidth, monitorWidthMM int64, rounding Rounding) (int64, error) {
+	var pixels int64
+	switch s.Unit {
+	case UnitPercent:
+		return monitorWidth - 2*CalculateGapSize(monitorWidth, s.Value, rounding), nil
+	case UnitPixels:
+		pixels = int64(s.Value)
+	case UnitCentimeters:
+		if monitorWidthMM <= 0 {
+			return 0, ErrPhysicalSizeUnknown
+		}
+		pixels = rounding.pixels(s.Value * 10 * float64(monitorWidth) / float64(monitorWidthMM))
+	}
+
+	if pixels < 1 || pixels > monitorWidth {
+		return 0, fmt.Errorf("%w: %s is %dpx, monitor is %dpx wide", ErrInvalidSize, s, pixels, monitorWidth)
+	}
+	return pixels, nil
+}
+
+// PercentageOf returns the percentage of monitorWidth that workspaceWidth
+// takes, rounded with RoundPercentage and clamped to the valid range.
+func PercentageOf(monitorWidth, workspaceWidth int64) float64 {
+	p := RoundPercentage(float64(workspaceWidth) * 100 / float64(monitorWidth))
+	return min(max(p, 1), 100)
+}
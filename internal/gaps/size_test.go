@@ -0,0 +1,87 @@
+package gaps
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Size
+	}{
+		{"60", Size{60, UnitPercent}},
+		{"60%", Size{60, UnitPercent}},
+		{"62.5", Size{62.5, UnitPercent}},
+		{"33.333%", Size{33.33, UnitPercent}},
+		{"2560px", Size{2560, UnitPixels}},
+		{"2560 PX", Size{2560, UnitPixels}},
+		{"60cm", Size{60, UnitCentimeters}},
+		{"62.5cm", Size{62.5, UnitCentimeters}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSize(tt.input)
+			if err != nil {
+				t.Fatalf("ParseSize(%q) error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSize(%q) = %+v; want %+v", tt.input, got, tt.want)
+			}
+			if again, _ := ParseSize(got.String()); again != got {
+				t.Errorf("ParseSize(%q.String()) = %+v; want %+v", tt.input, again, got)
+			}
+		})
+	}
+}
+
+func TestParseSizeInvalid(t *testing.T) {
+	for _, input := range []string{"", "abc", "-5", "0px", "12.5px", "cm", "10in"} {
+		if _, err := ParseSize(input); !errors.Is(err, ErrInvalidSize) {
+			t.Errorf("ParseSize(%q) error = %v; want ErrInvalidSize", input, err)
+		}
+	}
+}
+
+func TestSizePixels(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    Size
+		width   int64
+		widthMM int64
+		want    int64
+		wantErr error
+	}{
+		{"pixels", Size{2560, UnitPixels}, 5120, 0, 2560, nil},
+		{"percent", Size{50, UnitPercent}, 5120, 0, 2560, nil},
+		{"fractional percent", Size{62.5, UnitPercent}, 5120, 0, 3200, nil},
+		{"centimeters", Size{60, UnitCentimeters}, 5120, 1190, 2582, nil},
+		{"wider than monitor", Size{6000, UnitPixels}, 5120, 0, 0, ErrInvalidSize},
+		{"unknown physical width", Size{60, UnitCentimeters}, 5120, 0, 0, ErrPhysicalSizeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.size.Pixels(tt.width, tt.widthMM, RoundNearest)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Pixels() error = %v; want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Pixels() = %d; want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateShiftedGapsForWidth(t *testing.T) {
+	got := CalculateShiftedGapsForWidth(5120, 2560, 5, RoundNearest)
+	want := ShiftedGaps{LeftGapPercent: 30, RightGapPercent: 20, LeftGapPixels: 1536, RightGapPixels: 1024, WorkspacePixels: 2560}
+	if got != want {
+		t.Errorf("CalculateShiftedGapsForWidth() = %+v; want %+v", got, want)
+	}
+
+	if err := ValidateShiftForWidth(5120, 2560, 26, RoundNearest); !errors.Is(err, ErrInvalidShift) {
+		t.Errorf("ValidateShiftForWidth() error = %v; want ErrInvalidShift", err)
+	}
+}
@@ -0,0 +1,191 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Format is a structured output format selected with --output.
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// ParseFormat validates an --output value.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatText, FormatJSON, FormatYAML:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown output format %q (expected text, json or yaml)", s)
+}
+
+// Structured reports whether the format is machine-readable.
+func (f Format) Structured() bool {
+	return f == FormatJSON || f == FormatYAML
+}
+
+// Encode writes v to stdout as JSON or YAML. Field order follows the JSON
+// encoding of v, so struct types give a stable schema in both formats.
+func Encode(format Format, v any) error {
+	return EncodeTo(os.Stdout, format, v)
+}
+
+// EncodeTo writes v to w as JSON or YAML.
+func EncodeTo(w io.Writer, format Format, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode output: %w", err)
+	}
+
+	if format == FormatYAML {
+		if data, err = jsonToYAML(data); err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+	} else {
+		data = append(data, '\n')
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// jsonToYAML re-emits a JSON document as block-style YAML, keeping key order.
+func jsonToYAML(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	root, err := readYAMLNode(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writeYAMLNode(&buf, root, 0)
+	return buf.Bytes(), nil
+}
+
+// yamlNode is an ordered JSON value: a scalar, a list or a mapping.
+type yamlNode struct {
+	scalar string
+	list   []*yamlNode
+	keys   []string
+	values []*yamlNode
+	kind   json.Delim // '[' or '{' for collections, 0 for scalars
+}
+
+func readYAMLNode(dec *json.Decoder) (*yamlNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		n := &yamlNode{kind: t}
+		for dec.More() {
+			if t == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				n.keys = append(n.keys, key.(string))
+			}
+			child, err := readYAMLNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			if t == '{' {
+				n.values = append(n.values, child)
+			} else {
+				n.list = append(n.list, child)
+			}
+		}
+		// Consume the closing delimiter.
+		_, err := dec.Token()
+		return n, err
+	case string:
+		return &yamlNode{scalar: yamlScalar(t)}, nil
+	case nil:
+		return &yamlNode{scalar: "null"}, nil
+	default:
+		return &yamlNode{scalar: fmt.Sprint(t)}, nil
+	}
+}
+
+// inline returns the node's text when it fits after a key or list marker.
+func (n *yamlNode) inline() (string, bool) {
+	switch {
+	case n.kind == 0:
+		return n.scalar, true
+	case n.kind == '{' && len(n.keys) == 0:
+		return "{}", true
+	case n.kind == '[' && len(n.list) == 0:
+		return "[]", true
+	}
+	return "", false
+}
+
+// writeYAMLNode writes a collection node at the given indentation level.
+func writeYAMLNode(buf *bytes.Buffer, n *yamlNode, indent int) {
+	if text, ok := n.inline(); ok {
+		buf.WriteString(text + "\n")
+		return
+	}
+
+	pad := strings.Repeat("  ", indent)
+	if n.kind == '{' {
+		for i, key := range n.keys {
+			writeYAMLEntry(buf, pad+yamlScalar(key)+":", n.values[i], indent+1)
+		}
+		return
+	}
+
+	for _, item := range n.list {
+		if item.kind == '{' && len(item.keys) > 0 {
+			// Compact form: the first key shares the line with the dash.
+			var sub bytes.Buffer
+			writeYAMLNode(&sub, item, indent+1)
+			buf.WriteString(pad + "- " + strings.TrimPrefix(sub.String(), pad+"  "))
+			continue
+		}
+		writeYAMLEntry(buf, pad+"-", item, indent+1)
+	}
+}
+
+func writeYAMLEntry(buf *bytes.Buffer, prefix string, value *yamlNode, indent int) {
+	if text, ok := value.inline(); ok {
+		buf.WriteString(prefix + " " + text + "\n")
+		return
+	}
+	buf.WriteString(prefix + "\n")
+	writeYAMLNode(buf, value, indent)
+}
+
+var plainYAMLScalar = regexp.MustCompile(`^[A-Za-z_/][A-Za-z0-9_ ./-]*$`)
+
+// yamlScalar returns s as a plain scalar when unambiguous, otherwise as a
+// double-quoted scalar (JSON string syntax is valid YAML).
+func yamlScalar(s string) string {
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "y", "n":
+		return quoteYAML(s)
+	}
+	if plainYAMLScalar.MatchString(s) && !strings.HasSuffix(s, " ") {
+		return s
+	}
+	return quoteYAML(s)
+}
+
+func quoteYAML(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
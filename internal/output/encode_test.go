@@ -0,0 +1,65 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncodeYAML(t *testing.T) {
+	type monitor struct {
+		Name  string `json:"name"`
+		Width *int64 `json:"width"`
+		Main  bool   `json:"main"`
+	}
+	width := int64(1920)
+	v := struct {
+		Path     string         `json:"path"`
+		Monitors []monitor      `json:"monitors"`
+		Empty    []string       `json:"empty"`
+		Extra    map[string]int `json:"extra"`
+		Tags     []string       `json:"tags"`
+	}{
+		Path: "/tmp/aerospace.toml",
+		Monitors: []monitor{
+			{Name: "main", Width: &width, Main: true},
+			{Name: "Built-in Retina Display"},
+		},
+		Empty: []string{},
+		Tags:  []string{"yes", "a: b", "60%"},
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeTo(&buf, FormatYAML, v); err != nil {
+		t.Fatalf("EncodeTo() error: %v", err)
+	}
+
+	want := `path: /tmp/aerospace.toml
+monitors:
+  - name: main
+    width: 1920
+    main: true
+  - name: Built-in Retina Display
+    width: null
+    main: false
+empty: []
+extra: null
+tags:
+  - "yes"
+  - "a: b"
+  - "60%"
+`
+	if got := buf.String(); got != want {
+		t.Errorf("EncodeTo() mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, s := range []string{"text", "json", "YAML"} {
+		if _, err := ParseFormat(s); err != nil {
+			t.Errorf("ParseFormat(%q) error: %v", s, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(\"xml\") expected error")
+	}
+}
@@ -0,0 +1,39 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"sync"
+)
+
+// LineLogger is a writer that timestamps each complete line written to it,
+// for long-running commands whose output is a log. It is safe for
+// concurrent use.
+type LineLogger struct {
+	mu  sync.Mutex
+	log *log.Logger
+	buf []byte
+}
+
+// NewLineLogger returns a LineLogger writing to w.
+func NewLineLogger(w io.Writer) *LineLogger {
+	return &LineLogger{log: log.New(w, "", log.LstdFlags)}
+}
+
+// Write logs every complete line in p, keeping any partial line until the
+// rest of it arrives.
+func (l *LineLogger) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.buf = append(l.buf, p...)
+	for {
+		i := bytes.IndexByte(l.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		l.log.Print(string(l.buf[:i]))
+		l.buf = l.buf[i+1:]
+	}
+}
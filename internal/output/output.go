@@ -3,6 +3,7 @@ package output
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/fatih/color"
@@ -10,6 +11,7 @@ import (
 
 // Printer handles formatted console output with optional colors.
 type Printer struct {
+	w       io.Writer
 	label   *color.Color
 	value   *color.Color
 	path    *color.Color
@@ -27,6 +29,7 @@ func New(noColor bool) *Printer {
 	}
 
 	return &Printer{
+		w:       os.Stdout,
 		label:   color.New(color.FgCyan),
 		value:   color.New(color.FgGreen),
 		path:    color.New(color.Faint),
@@ -37,39 +40,45 @@ func New(noColor bool) *Printer {
 	}
 }
 
+// SetWriter redirects the printer's output, e.g. to stderr when stdout
+// carries a structured document.
+func (p *Printer) SetWriter(w io.Writer) {
+	p.w = w
+}
+
 // Label prints a cyan label.
 func (p *Printer) Label(format string, a ...interface{}) {
-	_, _ = p.label.Printf(format, a...)
+	_, _ = p.label.Fprintf(p.w, format, a...)
 }
 
 // Value prints a green value.
 func (p *Printer) Value(format string, a ...interface{}) {
-	_, _ = p.value.Printf(format, a...)
+	_, _ = p.value.Fprintf(p.w, format, a...)
 }
 
 // Path prints a dimmed path.
 func (p *Printer) Path(format string, a ...interface{}) {
-	_, _ = p.path.Printf(format, a...)
+	_, _ = p.path.Fprintf(p.w, format, a...)
 }
 
 // Unset prints a yellow "unset" indicator.
 func (p *Printer) Unset(format string, a ...interface{}) {
-	_, _ = p.unset.Printf(format, a...)
+	_, _ = p.unset.Fprintf(p.w, format, a...)
 }
 
 // Success prints a green success message.
 func (p *Printer) Success(format string, a ...interface{}) {
-	_, _ = p.success.Printf(format, a...)
+	_, _ = p.success.Fprintf(p.w, format, a...)
 }
 
 // Warning prints a yellow warning message.
 func (p *Printer) Warning(format string, a ...interface{}) {
-	_, _ = p.warning.Printf(format, a...)
+	_, _ = p.warning.Fprintf(p.w, format, a...)
 }
 
 // Error prints a red error message.
 func (p *Printer) Error(format string, a ...interface{}) {
-	_, _ = p.err.Printf(format, a...)
+	_, _ = p.err.Fprintf(p.w, format, a...)
 }
 
 // ReloadOK prints a success message for config reload.
@@ -115,5 +124,5 @@ func (p *Printer) PrintPath(label, path string) {
 
 // Printf prints formatted output without color.
 func (p *Printer) Printf(format string, a ...interface{}) {
-	fmt.Printf(format, a...)
+	_, _ = fmt.Fprintf(p.w, format, a...)
 }
@@ -5,10 +5,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/mholtzscher/aerospace-utils/cmd"
+	"github.com/mholtzscher/aerospace-utils/internal/aerospace"
+	"github.com/mholtzscher/aerospace-utils/internal/control"
 	"github.com/rogpeppe/go-internal/testscript"
 )
 
@@ -38,7 +42,35 @@ func TestScripts(t *testing.T) {
 				env.Setenv("TESTSCRIPT_BIN", parts[0])
 			}
 
+			// Never talk to a real aerospace instance; scripts use fake
+			// binaries in PATH instead.
+			env.Setenv(aerospace.EnvSocket, filepath.Join(env.WorkDir, "aerospace.sock"))
+			// Nor forward commands to a real aerospace-utils server.
+			env.Setenv(control.EnvSocket, filepath.Join(env.WorkDir, "aerospace-utils.sock"))
+			// Keep lock files and backups inside the script's work directory.
+			env.Setenv("XDG_RUNTIME_DIR", filepath.Join(env.WorkDir, ".run"))
+			env.Setenv("XDG_STATE_HOME", filepath.Join(env.WorkDir, ".state"))
+
 			return nil
 		},
+		Cmds: map[string]func(ts *testscript.TestScript, neg bool, args []string){
+			"waitfor": waitFor,
+		},
 	})
 }
+
+// waitFor waits for a file, such as a server's socket, to appear.
+func waitFor(ts *testscript.TestScript, neg bool, args []string) {
+	if neg || len(args) != 1 {
+		ts.Fatalf("usage: waitfor path")
+	}
+
+	path := ts.MkAbs(args[0])
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); {
+		if _, err := os.Stat(path); err == nil {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	ts.Fatalf("timed out waiting for %s", args[0])
+}